@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/axidex/Unknown/pkg/logger"
 	"github.com/axidex/elliptic/config"
 	"github.com/axidex/elliptic/internal/api"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/keystore"
+	"github.com/axidex/elliptic/internal/server"
+	"github.com/axidex/elliptic/internal/tracing"
 	"github.com/joho/godotenv"
 )
 
@@ -14,7 +19,7 @@ func main() {
 		fmt.Printf("Got error when reading config from file - %s", err)
 		return
 	}
-	fmt.Printf("Config: %+v\n", appConfig)
+	fmt.Printf("Config: %+v\n", appConfig.Redacted())
 
 	appLogger, err := logger.CreateNewZapLogger(appConfig.Logger)
 	if err != nil {
@@ -24,15 +29,66 @@ func main() {
 
 	err = godotenv.Load()
 
+	keys, err := keystore.Open(appConfig.KeyStore, appConfig.Postgres)
+	if err != nil {
+		appLogger.Fatal("Failed to open key store - %s", err)
+		return
+	}
+	defer keys.Close()
+
+	if appConfig.KMS.Enabled {
+		masterKey, err := keystore.ParseMasterKey(appConfig.KMS.MasterKey)
+		if err != nil {
+			appLogger.Fatal("Failed to read KMS master key - %s", err)
+			return
+		}
+		keys, err = keystore.NewSealedStore(keys, masterKey)
+		if err != nil {
+			appLogger.Fatal("Failed to open key store - %s", err)
+			return
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), appConfig.Tracing)
+	if err != nil {
+		appLogger.Fatal("Failed to set up tracing - %s", err)
+		return
+	}
+	defer shutdownTracing(context.Background())
+
+	var auditLog *audit.Log
+	if appConfig.Audit.Enabled {
+		auditLog, err = audit.Open(appConfig.Audit.Path)
+		if err != nil {
+			appLogger.Fatal("Failed to open audit log - %s", err)
+			return
+		}
+		defer auditLog.Close()
+	}
+
 	// App
-	app := api.CreateApp(appConfig, appLogger)
-	engine := app.InitRoutes()
+	app, err := api.CreateApp(appConfig, appLogger, keys, auditLog)
+	if err != nil {
+		appLogger.Fatal("Failed to create app - %s", err)
+		return
+	}
+	engine, err := app.InitRoutes()
+	if err != nil {
+		appLogger.Fatal("Failed to init routes - %s", err)
+		return
+	}
 	for _, item := range engine.Routes() {
 		appLogger.Info("method:", item.Method, "\tpath:", item.Path)
 	}
-	err = engine.Run(fmt.Sprintf(":%d", appConfig.Server.Port))
+
+	httpServer, err := server.New(appConfig.Server, engine, appLogger)
+	if err != nil {
+		appLogger.Fatal("Failed to create server - %s", err)
+		return
+	}
+	err = httpServer.Run(app.Drain)
 	if err != nil {
-		appLogger.Fatal("Failed to start server - %s", err)
+		appLogger.Fatal("Server stopped with error - %s", err)
 		return
 	}
 
@@ -3,15 +3,121 @@ package config
 import (
 	"github.com/axidex/Unknown/pkg/logger"
 	"github.com/spf13/viper"
+	"time"
 )
 
 type Config struct {
-	Server Server              `yaml:"server"`
-	Logger logger.ConfigLogger `yaml:"logger"`
+	Server   Server              `yaml:"server"`
+	Logger   logger.ConfigLogger `yaml:"logger"`
+	Postgres Postgres            `yaml:"postgres"`
+	KeyStore KeyStore            `yaml:"keyStore"`
+	KMS      KMS                 `yaml:"kms"`
+	Auth     Auth                `yaml:"auth"`
+	Tracing  Tracing             `yaml:"tracing"`
+	Audit    Audit               `yaml:"audit"`
 }
 
 type Server struct {
-	Port int `yaml:"port"`
+	Port              int       `yaml:"port"`
+	MaxBodyBytes      int64     `yaml:"maxBodyBytes"`      // 0 - без ограничения
+	MaxPlaintextBytes int       `yaml:"maxPlaintextBytes"` // 0 - без ограничения
+	RateLimit         RateLimit `yaml:"rateLimit"`
+	TLS               TLS       `yaml:"tls"`
+
+	// TrustedProxies lists the IPs and CIDRs of reverse proxies whose
+	// X-Forwarded-For header gives the client IP. Empty trusts none, so the
+	// client IP is the address of the connection.
+	TrustedProxies []string `yaml:"trustedProxies"`
+
+	// Таймауты в формате 10s, 1m; 0 - значение по умолчанию
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+	ReadTimeout       time.Duration `yaml:"readTimeout"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout"`
+	MaxHeaderBytes    int           `yaml:"maxHeaderBytes"` // 0 - 1 МБ
+
+	// DrainDelay is how long the readiness check fails before the server
+	// stops accepting connections, ShutdownTimeout how long in-flight
+	// requests then get to finish.
+	DrainDelay      time.Duration `yaml:"drainDelay"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+}
+
+// TLS serves the API over HTTPS. The certificate is reloaded on SIGHUP.
+type TLS struct {
+	Enabled    bool     `yaml:"enabled"`
+	CertFile   string   `yaml:"certFile"`
+	KeyFile    string   `yaml:"keyFile"`
+	MinVersion string   `yaml:"minVersion"` // 1.2 или 1.3
+	ClientCA   string   `yaml:"clientCA"`   // CA для mTLS, пусто - без проверки клиентов
+	SelfSigned bool     `yaml:"selfSigned"` // Создать самоподписанный сертификат, если файлов нет
+	Curve      string   `yaml:"curve"`      // Кривая ключа самоподписанного сертификата
+	Hosts      []string `yaml:"hosts"`      // DNS имена и IP самоподписанного сертификата
+}
+
+// RateLimit configures token buckets kept per client and route. Clients are
+// identified by the authenticated principal, or by IP when auth is disabled.
+// With auth enabled every IP also gets one bucket over all routes that is
+// checked before authentication, so failed attempts are limited too.
+type RateLimit struct {
+	Enabled bool        `yaml:"enabled"`
+	Rate    float64     `yaml:"rate"`  // Запросов в секунду
+	Burst   int         `yaml:"burst"` // Размер корзины
+	Routes  []RouteRate `yaml:"routes"`
+
+	// Лимит на IP до аутентификации; 0 - как rate и burst
+	IPRate  float64 `yaml:"ipRate"`
+	IPBurst int     `yaml:"ipBurst"`
+}
+
+// RouteRate overrides the default rate for a single route.
+type RouteRate struct {
+	Path  string  `yaml:"path"` // Например /api/cypher/elliptic/encrypt
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+type Postgres struct {
+	URL      string `yaml:"url"`
+	Port     int    `yaml:"port"`
+	Schema   string `yaml:"schema"` // Имя базы данных
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	SSLMode  string `yaml:"sslMode"`
+}
+
+// KeyStore selects where server-side keys are kept.
+type KeyStore struct {
+	Backend string `yaml:"backend"` // file, sqlite или postgres
+	Path    string `yaml:"path"`    // Каталог для file, файл базы для sqlite
+}
+
+// KMS keeps private keys on the server only. Stored keys are wrapped with the
+// master key, and clients decrypt by key ID.
+type KMS struct {
+	Enabled   bool   `yaml:"enabled"`
+	MasterKey string `yaml:"masterKey"` // 32 байта в base64
+}
+
+// Redacted returns a copy of the config with passwords, keys and secrets
+// blanked out, safe to print or log.
+func (c Config) Redacted() Config {
+	const hidden = "[REDACTED]"
+	if c.Postgres.Password != "" {
+		c.Postgres.Password = hidden
+	}
+	if c.KMS.MasterKey != "" {
+		c.KMS.MasterKey = hidden
+	}
+	c.Auth.APIKeys = append([]APIKey(nil), c.Auth.APIKeys...)
+	for i := range c.Auth.APIKeys {
+		c.Auth.APIKeys[i].Key = hidden
+	}
+	c.Auth.HMAC = append([]HMACClient(nil), c.Auth.HMAC...)
+	for i := range c.Auth.HMAC {
+		c.Auth.HMAC[i].Secret = hidden
+	}
+	return c
 }
 
 func ReadConfig() (*Config, error) {
@@ -31,3 +137,47 @@ func ReadConfig() (*Config, error) {
 
 	return &config, nil
 }
+
+// Auth protects the API. Clients are identified by a static API key, an
+// HMAC-signed request or a JWT bearer token, and each route requires a scope.
+type Auth struct {
+	Enabled bool         `yaml:"enabled"`
+	APIKeys []APIKey     `yaml:"apiKeys"`
+	HMAC    []HMACClient `yaml:"hmac"`
+	JWT     JWT          `yaml:"jwt"`
+}
+
+type APIKey struct {
+	Name   string   `yaml:"name"`
+	Key    string   `yaml:"key"`
+	Scopes []string `yaml:"scopes"`
+}
+
+type HMACClient struct {
+	ID     string   `yaml:"id"`
+	Secret string   `yaml:"secret"`
+	Scopes []string `yaml:"scopes"`
+}
+
+type JWT struct {
+	PublicKeys []string `yaml:"publicKeys"` // Пути к PEM публичным ключам
+	Issuer     string   `yaml:"issuer"`
+	Audience   string   `yaml:"audience"`
+}
+
+// Tracing exports OpenTelemetry spans of API requests and cypher operations.
+type Tracing struct {
+	Enabled     bool    `yaml:"enabled"`
+	Exporter    string  `yaml:"exporter"` // stdout или otlp
+	Endpoint    string  `yaml:"endpoint"` // host:port OTLP/HTTP коллектора
+	Insecure    bool    `yaml:"insecure"` // OTLP без TLS
+	ServiceName string  `yaml:"serviceName"`
+	SampleRatio float64 `yaml:"sampleRatio"` // Доля трассируемых запросов, 0 - все
+}
+
+// Audit records key generation, encryption and decryption to a hash-chained
+// append-only file.
+type Audit struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
@@ -15,9 +15,29 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/cypher/elliptic/decrypt": {
+        "/api/cypher/capabilities": {
+            "get": {
+                "description": "List the supported curves with the suites usable on them, the suites, encryption schemes with their caveats, KDFs and encodings together with their security levels in bits",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keys"
+                ],
+                "summary": "List capabilities",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Capabilities"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elgamal/decrypt": {
             "post": {
-                "description": "Decrypt the provided text using the given public key",
+                "description": "Decrypt the provided EC-ElGamal ciphertext using the given private key or, in KMS mode, only the stored key",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,9 +45,9 @@ const docTemplate = `{
                     "text/plain"
                 ],
                 "tags": [
-                    "encryption"
+                    "schemes"
                 ],
-                "summary": "Decrypt data",
+                "summary": "Decrypt data with EC-ElGamal",
                 "parameters": [
                     {
                         "description": "Payload",
@@ -49,23 +69,33 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/cypher/elliptic/encrypt": {
+        "/api/cypher/elgamal/encrypt": {
             "post": {
-                "description": "Encrypt the provided text using the given public key",
+                "description": "Encrypt the provided text using EC-ElGamal with Koblitz encoding and the given public key or stored key",
                 "consumes": [
                     "application/json"
                 ],
@@ -73,9 +103,9 @@ const docTemplate = `{
                     "text/plain"
                 ],
                 "tags": [
-                    "encryption"
+                    "schemes"
                 ],
-                "summary": "Encrypt data",
+                "summary": "Encrypt data with EC-ElGamal",
                 "parameters": [
                     {
                         "description": "Payload",
@@ -97,23 +127,33 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/cypher/elliptic/keys": {
-            "get": {
-                "description": "Generate a public key using the elliptic curve algorithm",
+        "/api/cypher/elliptic/agree": {
+            "post": {
+                "description": "Run ECDH between the private key or stored key and the peer public key and derive a key with HKDF. In KMS mode only stored keys are accepted",
                 "consumes": [
                     "application/json"
                 ],
@@ -123,42 +163,2442 @@ const docTemplate = `{
                 "tags": [
                     "keys"
                 ],
-                "summary": "Generate a public key",
+                "summary": "Derive a shared key",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AgreeRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.Keys"
+                            "$ref": "#/definitions/api.SharedKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.EncryptRequest": {
-            "type": "object",
-            "required": [
-                "pemKey",
-                "text"
-            ],
-            "properties": {
-                "pemKey": {
-                    "description": "Ключ как строка",
-                    "type": "string"
-                },
-                "text": {
-                    "type": "string"
+        },
+        "/api/cypher/elliptic/datakey": {
+            "post": {
+                "description": "Generate a random AES key and return it in plaintext and wrapped with ECIES under the given public key or stored key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datakey"
+                ],
+                "summary": "Generate a data key",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.DataKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "api.Keys": {
-            "type": "object",
-            "properties": {
-                "private": {
-                    "type": "string"
-                },
-                "public": {
+        "/api/cypher/elliptic/datakey/unwrap": {
+            "post": {
+                "description": "Decrypt a wrapped data key with the given private key or, in KMS mode, only the stored key with the given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datakey"
+                ],
+                "summary": "Unwrap a data key",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UnwrapDataKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/decrypt": {
+            "post": {
+                "description": "Decrypt the provided text using the given private key or, in KMS mode, only the stored key with the given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "encryption"
+                ],
+                "summary": "Decrypt data",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EncryptRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return an ExplainedResult JSON with the intermediate values instead of plain text",
+                        "name": "explain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Decrypted data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/decrypt/file": {
+            "post": {
+                "description": "Decrypt a binary ciphertext produced by /encrypt/file with the given private key or, in KMS mode, only the stored key. The request has the same forms as /encrypt/file and the plaintext is returned as a download",
+                "consumes": [
+                    "multipart/form-data",
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Decrypt a file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Data to decrypt",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "PEM private key",
+                        "name": "pemKey",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stored key ID",
+                        "name": "keyId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stored key ID for octet-stream bodies",
+                        "name": "keyId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "File name for octet-stream bodies",
+                        "name": "filename",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Decrypted data",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/encrypt": {
+            "post": {
+                "description": "Encrypt the provided text using the given public key or the stored key with the given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "encryption"
+                ],
+                "summary": "Encrypt data",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EncryptRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return an ExplainedResult JSON with the intermediate values instead of plain text",
+                        "name": "explain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Encrypted data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/encrypt/file": {
+            "post": {
+                "description": "Encrypt a binary file with the given public key or stored key. Send a multipart form with the data in \"file\" and the key in \"pemKey\" or \"keyId\", or the raw data as application/octet-stream with keyId in the query. The binary ciphertext is returned as a download",
+                "consumes": [
+                    "multipart/form-data",
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Encrypt a file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Data to encrypt",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "PEM public key",
+                        "name": "pemKey",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stored key ID",
+                        "name": "keyId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stored key ID for octet-stream bodies",
+                        "name": "keyId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "File name for octet-stream bodies",
+                        "name": "filename",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Encrypted data",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/hybrid/decrypt": {
+            "post": {
+                "description": "Decrypt the provided text using the given hybrid ECDH + ML-KEM-768 private key or, in KMS mode, only the stored hybrid key with the given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "hybrid"
+                ],
+                "summary": "Decrypt data with a hybrid key",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EncryptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Decrypted data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/hybrid/encrypt": {
+            "post": {
+                "description": "Encrypt the provided text using the given hybrid ECDH + ML-KEM-768 public key or the stored hybrid key with the given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "hybrid"
+                ],
+                "summary": "Encrypt data with a hybrid key",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EncryptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Encrypted data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/hybrid/keys": {
+            "get": {
+                "description": "Generate an elliptic curve key pair combined with an ML-KEM-768 key pair. In KMS mode the private key is kept on the server and its ID is returned instead",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hybrid"
+                ],
+                "summary": "Generate a hybrid key pair",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Keys"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/keys": {
+            "get": {
+                "description": "Generate a keypair using the elliptic curve algorithm. In KMS mode the private key is kept on the server and its ID is returned instead. See /api/cypher/capabilities for the curves and suites",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keys"
+                ],
+                "summary": "Generate a public key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "P-256",
+                        "description": "Curve name",
+                        "name": "curve",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Suite name, the curve's default suite if empty",
+                        "name": "suite",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Keys"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/reencrypt": {
+            "post": {
+                "description": "Transform a ciphertext for the delegator into one for the delegatee without decrypting it. The proxy learns nothing on its own, but must not collude with the delegatee, see /rekey",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "reencryption"
+                ],
+                "summary": "Re-encrypt data",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ReencryptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Re-encrypted data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/rekey": {
+            "post": {
+                "description": "Delegate decryption from the owner of the private key to the owner of the public key. The scheme is not collusion-safe: the re-encryption key together with the delegatee private key reveals the delegator private key, so give it only to a proxy that never colludes with the delegatee. Not available in KMS mode",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reencryption"
+                ],
+                "summary": "Generate a re-encryption key",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ReencryptionKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReencryptionKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/threshold/combine": {
+            "post": {
+                "description": "Combine at least threshold decryption shares and decrypt the ciphertext",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "threshold"
+                ],
+                "summary": "Combine decryption shares",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CombineSharesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Decrypted data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/threshold/partial": {
+            "post": {
+                "description": "Compute the decryption share of one key share for the given ciphertext",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "threshold"
+                ],
+                "summary": "Partially decrypt data",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.PartialDecryptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DecryptionShare"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/elliptic/threshold/split": {
+            "post": {
+                "description": "Split the private key into Shamir shares, any threshold of which can decrypt together. Not available in KMS mode",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "threshold"
+                ],
+                "summary": "Split a private key",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SplitKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.KeyShares"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/menezes-vanstone/decrypt": {
+            "post": {
+                "description": "Decrypt the provided Menezes–Vanstone ciphertext using the given private key or, in KMS mode, only the stored key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Decrypt data with Menezes–Vanstone",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EncryptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Decrypted data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cypher/menezes-vanstone/encrypt": {
+            "post": {
+                "description": "Encrypt the provided text using the Menezes–Vanstone cryptosystem and the given public key or stored key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Encrypt data with Menezes–Vanstone",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EncryptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Encrypted data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keys": {
+            "get": {
+                "description": "List the keys in the key store with their public halves",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keystore"
+                ],
+                "summary": "List stored keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.StoredKey"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Generate a keypair on the server and keep the private key in the key store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keystore"
+                ],
+                "summary": "Generate a stored key",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.StoredKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keys/{id}": {
+            "get": {
+                "description": "Get the public half of a stored key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keystore"
+                ],
+                "summary": "Get a stored key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.StoredKey"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a key from the key store",
+                "tags": [
+                    "keystore"
+                ],
+                "summary": "Delete a stored key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/toy/add": {
+            "post": {
+                "description": "Compute p + q on a small curve",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "toy"
+                ],
+                "summary": "Add two points",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AddPointsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.PointResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/toy/dlog": {
+            "post": {
+                "description": "Recover k with q = k·g on a small curve using baby-step giant-step, Pollard's rho or Pohlig–Hellman",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "toy"
+                ],
+                "summary": "Solve a discrete logarithm",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.DiscreteLogRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DiscreteLogResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/toy/double": {
+            "post": {
+                "description": "Compute 2p on a small curve",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "toy"
+                ],
+                "summary": "Double a point",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.PointRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.PointResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/toy/group-order": {
+            "get": {
+                "description": "Count the points of a small curve, including the point at infinity",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "toy"
+                ],
+                "summary": "Group order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Coefficient a",
+                        "name": "a",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Coefficient b",
+                        "name": "b",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Prime modulus",
+                        "name": "p",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.OrderResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/toy/multiply": {
+            "post": {
+                "description": "Compute k·p on a small curve and return every step of the double-and-add ladder",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "toy"
+                ],
+                "summary": "Multiply a point by a scalar",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.MultiplyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.MultiplyResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/toy/order": {
+            "post": {
+                "description": "Compute the order of a point on a small curve",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "toy"
+                ],
+                "summary": "Point order",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.PointRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.OrderResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/toy/points": {
+            "get": {
+                "description": "List every point of a small curve y^2 = x^3 + ax + b over F_p",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "toy"
+                ],
+                "summary": "List curve points",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Coefficient a",
+                        "name": "a",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Coefficient b",
+                        "name": "b",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Prime modulus",
+                        "name": "p",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.CurvePoints"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/decrypt": {
+            "post": {
+                "description": "Decrypt the ciphertext with the given private key or, in KMS mode, only the stored key. Plaintexts that aren't valid UTF-8 must be requested as base64 or hex",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Decrypt data",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.DecryptRequestV2"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DecryptResponseV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/encrypt": {
+            "post": {
+                "description": "Encrypt the plaintext with the given public key or stored key. The ciphertext is the same as of /api/cypher/elliptic/encrypt, so both versions can decrypt it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Encrypt data",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EncryptRequestV2"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.EncryptResponseV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/inspect": {
+            "post": {
+                "description": "Describe a PEM public key or the public half of a stored key: its curve, suite, security level and fingerprint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Inspect a key",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.InspectRequestV2"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.KeyInfoV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/keys": {
+            "post": {
+                "description": "Generate a keypair on the given curve with the given suite. In KMS mode the private key is kept on the server and its ID is returned instead. See /api/cypher/capabilities for the curves and suites",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Generate a keypair",
+                "parameters": [
+                    {
+                        "description": "Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.GenerateKeyRequestV2"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.KeyPairV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.AddPointsRequest": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "$ref": "#/definitions/api.EllipticArgs"
+                },
+                "p": {
+                    "$ref": "#/definitions/api.PublicKey"
+                },
+                "q": {
+                    "$ref": "#/definitions/api.PublicKey"
+                }
+            }
+        },
+        "api.AgreeRequest": {
+            "type": "object",
+            "required": [
+                "length",
+                "publicKey"
+            ],
+            "properties": {
+                "info": {
+                    "description": "Необязательно",
+                    "type": "string"
+                },
+                "keyId": {
+                    "description": "Или ID ключа в хранилище",
+                    "type": "string"
+                },
+                "length": {
+                    "description": "Длина ключа в байтах",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "pemKey": {
+                    "description": "Свой приватный ключ",
+                    "type": "string"
+                },
+                "publicKey": {
+                    "description": "Публичный ключ собеседника",
+                    "type": "string"
+                },
+                "salt": {
+                    "description": "base64, необязательно",
+                    "type": "string"
+                }
+            }
+        },
+        "api.Capabilities": {
+            "type": "object",
+            "properties": {
+                "curves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.CurveCapability"
+                    }
+                },
+                "encodings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.EncodingCapability"
+                    }
+                },
+                "kdfs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.KDFCapability"
+                    }
+                },
+                "schemes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SchemeCapability"
+                    }
+                },
+                "suites": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SuiteCapability"
+                    }
+                }
+            }
+        },
+        "api.CombineSharesRequest": {
+            "type": "object",
+            "required": [
+                "partials",
+                "publicKey",
+                "text"
+            ],
+            "properties": {
+                "partials": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "publicKey": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CreateKeyRequest": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "description": "По умолчанию P-256",
+                    "type": "string"
+                },
+                "suite": {
+                    "description": "По умолчанию набор кривой",
+                    "type": "string"
+                }
+            }
+        },
+        "api.CurveCapability": {
+            "type": "object",
+            "properties": {
+                "bits": {
+                    "type": "integer"
+                },
+                "defaultSuite": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "oid": {
+                    "type": "string"
+                },
+                "securityBits": {
+                    "type": "integer"
+                },
+                "suites": {
+                    "description": "Наборы, совместимые с кривой",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.CurvePoints": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "type": "string"
+                },
+                "order": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.PublicKey"
+                    }
+                }
+            }
+        },
+        "api.DataKey": {
+            "type": "object",
+            "properties": {
+                "plaintext": {
+                    "description": "base64",
+                    "type": "string"
+                },
+                "wrapped": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DataKeyRequest": {
+            "type": "object",
+            "properties": {
+                "keyId": {
+                    "description": "Или ID ключа в хранилище",
+                    "type": "string"
+                },
+                "pemKey": {
+                    "description": "Публичный ключ",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "16, 24 или 32 байта, по умолчанию 32",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.DecryptRequestV2": {
+            "type": "object",
+            "required": [
+                "ciphertext"
+            ],
+            "properties": {
+                "ciphertext": {
+                    "type": "string"
+                },
+                "ciphertextEncoding": {
+                    "description": "base64 (по умолчанию) или hex",
+                    "type": "string"
+                },
+                "keyId": {
+                    "description": "Или ID ключа в хранилище",
+                    "type": "string"
+                },
+                "plaintextEncoding": {
+                    "description": "utf8 (по умолчанию), base64 или hex",
+                    "type": "string"
+                },
+                "privateKey": {
+                    "description": "Приватный ключ в PEM, не в режиме KMS",
+                    "type": "string"
+                }
+            }
+        },
+        "api.DecryptResponseV2": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "keyId": {
+                    "type": "string"
+                },
+                "plaintext": {
+                    "type": "string"
+                },
+                "suite": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DecryptionShare": {
+            "type": "object",
+            "properties": {
+                "partial": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DiscreteLogRequest": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "$ref": "#/definitions/api.EllipticArgs"
+                },
+                "g": {
+                    "$ref": "#/definitions/api.PublicKey"
+                },
+                "method": {
+                    "description": "bsgs, rho или pohlig-hellman, пустое значение - все методы",
+                    "type": "string"
+                },
+                "q": {
+                    "$ref": "#/definitions/api.PublicKey"
+                }
+            }
+        },
+        "api.DiscreteLogResult": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "type": "integer"
+                },
+                "solutions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.DiscreteLogSolution"
+                    }
+                }
+            }
+        },
+        "api.DiscreteLogSolution": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string"
+                },
+                "iterations": {
+                    "type": "integer"
+                },
+                "k": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "nanoseconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.EllipticArgs": {
+            "type": "object",
+            "required": [
+                "p"
+            ],
+            "properties": {
+                "a": {
+                    "description": "Coefficients of the curve equation y^2 = x^3 + Ax + B, zero is allowed",
+                    "type": "integer"
+                },
+                "b": {
+                    "type": "integer"
+                },
+                "p": {
+                    "description": "Prime modulus",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.EncodingCapability": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.EncryptRequest": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "keyId": {
+                    "description": "Или ID ключа в хранилище",
+                    "type": "string"
+                },
+                "pemKey": {
+                    "description": "Ключ как строка",
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.EncryptRequestV2": {
+            "type": "object",
+            "required": [
+                "plaintext"
+            ],
+            "properties": {
+                "ciphertextEncoding": {
+                    "description": "base64 (по умолчанию) или hex",
+                    "type": "string"
+                },
+                "keyId": {
+                    "description": "Или ID ключа в хранилище",
+                    "type": "string"
+                },
+                "plaintext": {
+                    "type": "string"
+                },
+                "plaintextEncoding": {
+                    "description": "utf8 (по умолчанию), base64 или hex",
+                    "type": "string"
+                },
+                "publicKey": {
+                    "description": "Публичный ключ в PEM",
+                    "type": "string"
+                }
+            }
+        },
+        "api.EncryptResponseV2": {
+            "type": "object",
+            "properties": {
+                "ciphertext": {
+                    "type": "string"
+                },
+                "curve": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "keyId": {
+                    "type": "string"
+                },
+                "suite": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Стабильный код ошибки",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Сообщение для человека",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Недостающее право доступа",
+                    "type": "string"
+                }
+            }
+        },
+        "api.GenerateKeyRequestV2": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "description": "По умолчанию P-256",
+                    "type": "string"
+                },
+                "suite": {
+                    "description": "По умолчанию набор кривой",
+                    "type": "string"
+                }
+            }
+        },
+        "api.InspectRequestV2": {
+            "type": "object",
+            "properties": {
+                "keyId": {
+                    "description": "Или ID ключа в хранилище",
+                    "type": "string"
+                },
+                "publicKey": {
+                    "description": "Публичный ключ в PEM",
+                    "type": "string"
+                }
+            }
+        },
+        "api.KDFCapability": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "hashes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.KeyInfoV2": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "type": "string"
+                },
+                "fingerprint": {
+                    "type": "string"
+                },
+                "keyId": {
+                    "type": "string"
+                },
+                "publicKey": {
+                    "type": "string"
+                },
+                "securityBits": {
+                    "type": "integer"
+                },
+                "suite": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.KeyPairV2": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "type": "string"
+                },
+                "keyId": {
+                    "description": "Только в режиме KMS",
+                    "type": "string"
+                },
+                "privateKey": {
+                    "description": "Не в режиме KMS",
+                    "type": "string"
+                },
+                "publicKey": {
+                    "type": "string"
+                },
+                "suite": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.KeyShares": {
+            "type": "object",
+            "properties": {
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.Keys": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Только в режиме KMS",
+                    "type": "string"
+                },
+                "private": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.LadderStep": {
+            "type": "object",
+            "properties": {
+                "bit": {
+                    "type": "integer"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "point": {
+                    "$ref": "#/definitions/api.PublicKey"
+                },
+                "scalar": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.MultiplyRequest": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "$ref": "#/definitions/api.EllipticArgs"
+                },
+                "k": {
+                    "type": "integer"
+                },
+                "p": {
+                    "$ref": "#/definitions/api.PublicKey"
+                }
+            }
+        },
+        "api.MultiplyResult": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "$ref": "#/definitions/api.PublicKey"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.LadderStep"
+                    }
+                }
+            }
+        },
+        "api.OrderResult": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.PartialDecryptRequest": {
+            "type": "object",
+            "required": [
+                "share",
+                "text"
+            ],
+            "properties": {
+                "share": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.PointRequest": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "$ref": "#/definitions/api.EllipticArgs"
+                },
+                "p": {
+                    "$ref": "#/definitions/api.PublicKey"
+                }
+            }
+        },
+        "api.PointResult": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "$ref": "#/definitions/api.PublicKey"
+                }
+            }
+        },
+        "api.PublicKey": {
+            "type": "object",
+            "properties": {
+                "infinity": {
+                    "description": "Точка на бесконечности",
+                    "type": "boolean"
+                },
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.ReencryptRequest": {
+            "type": "object",
+            "required": [
+                "reKey",
+                "text"
+            ],
+            "properties": {
+                "reKey": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ReencryptionKey": {
+            "type": "object",
+            "properties": {
+                "reKey": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ReencryptionKeyRequest": {
+            "type": "object",
+            "required": [
+                "pemKey",
+                "publicKey"
+            ],
+            "properties": {
+                "pemKey": {
+                    "description": "Приватный ключ делегирующего",
+                    "type": "string"
+                },
+                "publicKey": {
+                    "description": "Публичный ключ получателя",
+                    "type": "string"
+                }
+            }
+        },
+        "api.SchemeCapability": {
+            "type": "object",
+            "properties": {
+                "caveats": {
+                    "description": "Ограничения безопасности",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SharedKey": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "description": "base64",
+                    "type": "string"
+                }
+            }
+        },
+        "api.SplitKeyRequest": {
+            "type": "object",
+            "required": [
+                "pemKey",
+                "shares",
+                "threshold"
+            ],
+            "properties": {
+                "pemKey": {
+                    "type": "string"
+                },
+                "shares": {
+                    "type": "integer"
+                },
+                "threshold": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "api.StoredKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "curve": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "string"
+                },
+                "suite": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SuiteCapability": {
+            "type": "object",
+            "properties": {
+                "cipher": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "kdf": {
+                    "type": "string"
+                },
+                "keyBits": {
+                    "type": "integer"
+                },
+                "mac": {
+                    "type": "string"
+                },
+                "macBits": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "securityBits": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.UnwrapDataKeyRequest": {
+            "type": "object",
+            "required": [
+                "wrapped"
+            ],
+            "properties": {
+                "keyId": {
+                    "description": "Или ID ключа в хранилище",
+                    "type": "string"
+                },
+                "pemKey": {
+                    "description": "Приватный ключ",
+                    "type": "string"
+                },
+                "wrapped": {
                     "type": "string"
                 }
             }
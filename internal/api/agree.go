@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/base64"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Derive a shared key
+// @Description Run ECDH between the private key or stored key and the peer public key and derive a key with HKDF. In KMS mode only stored keys are accepted
+// @Tags keys
+// @Accept application/json
+// @Produce json
+// @Param payload body AgreeRequest true "Payload"
+// @Success 200 {object} SharedKey
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/agree [post]
+func (app *App) agree(c *gin.Context) {
+	var req AgreeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(req.Salt)
+	if err != nil {
+		app.logger.Warnf("Invalid salt: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "salt must be base64")
+		return
+	}
+
+	app.logger.Infof("Got task key agreement")
+
+	op := app.operation(c, audit.ActionAgree, "agree", req.Length)
+	key, ok := app.requestPrivateKey(c, req.PEMKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(&key.PublicKey)
+
+	peer, err := cypher.ImportPublicPEM([]byte(req.PublicKey))
+	if err != nil {
+		op.InvalidKey()
+		app.logger.Infof("Not valid key: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+		return
+	}
+
+	shared, err := key.Agree(peer, req.Length, salt, []byte(req.Info))
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Key agreement error %v", err)
+		failCypher(c, err, "key agreement error")
+		return
+	}
+
+	c.JSON(http.StatusOK, SharedKey{
+		Key: base64.StdEncoding.EncodeToString(shared),
+	})
+}
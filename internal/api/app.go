@@ -4,32 +4,77 @@ import (
 	"github.com/axidex/Unknown/pkg/logger"
 	"github.com/axidex/elliptic/config"
 	_ "github.com/axidex/elliptic/docs"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/auth"
+	"github.com/axidex/elliptic/internal/keystore"
+	"github.com/axidex/elliptic/internal/metrics"
+	"github.com/axidex/elliptic/internal/ratelimit"
 	ginzerolog "github.com/dn365/gin-zerolog"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"net/http"
+	"sync/atomic"
 )
 
 type App struct {
 	config *config.Config
 	logger logger.Logger
+	keys   keystore.Store
+	auth   auth.Authenticator
+	audit  *audit.Log
+
+	limiter   *ratelimit.Limiter
+	ipLimiter *ratelimit.Limiter
+	draining  atomic.Bool
 }
 
-func CreateApp(config *config.Config, logger logger.Logger) *App {
-	return &App{
+// CreateApp creates the API. auditLog may be nil to disable auditing.
+func CreateApp(config *config.Config, logger logger.Logger, keys keystore.Store, auditLog *audit.Log) (*App, error) {
+	authenticator, err := auth.New(config.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &App{
 		config: config,
 		logger: logger,
+		keys:   keys,
+		auth:   authenticator,
+		audit:  auditLog,
 	}
+	if limits := config.Server.RateLimit; limits.Enabled {
+		app.limiter = ratelimit.New(limits)
+		if config.Auth.Enabled {
+			app.ipLimiter = ratelimit.New(ipRateLimit(limits))
+		}
+	}
+	return app, nil
 }
 
-func (app *App) InitRoutes() *gin.Engine {
+func (app *App) InitRoutes() (*gin.Engine, error) {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	// ClientIP keys rate limits and audit events, so X-Forwarded-For is only
+	// believed when it comes from a configured proxy.
+	if err := router.SetTrustedProxies(app.config.Server.TrustedProxies); err != nil {
+		return nil, err
+	}
 	router.Use(gin.Recovery())
+	router.Use(otelgin.Middleware(app.serviceName()))
 	router.Use(gin.LoggerWithFormatter(app.loggerMiddleware))
+	router.Use(app.metricsMiddleware)
+	router.Use(app.limitBody)
 	//router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	router.GET("/swagger/*any", app.swagger)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	canEncrypt := app.require(auth.ScopeEncrypt)
+	canDecrypt := app.require(auth.ScopeDecrypt)
+	canGenerate := app.require(auth.ScopeKeysGenerate)
+	canRead := app.require(auth.ScopeKeysRead)
+	canDelete := app.require(auth.ScopeKeysDelete)
 
 	api := router.Group("/api")
 	{
@@ -37,22 +82,85 @@ func (app *App) InitRoutes() *gin.Engine {
 		health := api.Group("/health")
 		{
 			health.GET("/ping", app.health)
+			health.GET("/ready", app.ready)
 		}
 
-		cyphers := api.Group("/cypher")
+		cyphers := api.Group("/cypher", app.limitAddress, app.authenticate, app.rateLimit)
 		{
+			cyphers.GET("/capabilities", app.capabilities)
+
 			elliptic := cyphers.Group("/elliptic")
 			{
-				elliptic.POST("/encrypt", app.encrypt)
-				elliptic.GET("/keys", app.generateKey)
-				elliptic.POST("/decrypt", app.decrypt)
+				elliptic.POST("/encrypt", canEncrypt, app.encrypt)
+				elliptic.POST("/encrypt/file", canEncrypt, app.encryptFile)
+				elliptic.GET("/keys", canGenerate, app.generateKey)
+				elliptic.POST("/decrypt", canDecrypt, app.decrypt)
+				elliptic.POST("/decrypt/file", canDecrypt, app.decryptFile)
+				elliptic.POST("/rekey", canGenerate, app.generateReencryptionKey)
+				elliptic.POST("/reencrypt", canEncrypt, app.reencrypt)
+				elliptic.POST("/agree", canDecrypt, app.agree)
+				elliptic.POST("/datakey", canEncrypt, app.generateDataKey)
+				elliptic.POST("/datakey/unwrap", canDecrypt, app.unwrapDataKey)
+
+				threshold := elliptic.Group("/threshold")
+				{
+					threshold.POST("/split", canGenerate, app.splitKey)
+					threshold.POST("/partial", canDecrypt, app.partialDecrypt)
+					threshold.POST("/combine", canDecrypt, app.combineShares)
+				}
+
+				hybrid := elliptic.Group("/hybrid")
+				{
+					hybrid.POST("/encrypt", canEncrypt, app.encryptHybrid)
+					hybrid.GET("/keys", canGenerate, app.generateHybridKey)
+					hybrid.POST("/decrypt", canDecrypt, app.decryptHybrid)
+				}
+			}
+
+			elgamal := cyphers.Group("/elgamal")
+			{
+				elgamal.POST("/encrypt", canEncrypt, app.encryptElGamal)
+				elgamal.POST("/decrypt", canDecrypt, app.decryptElGamal)
 			}
+
+			menezesVanstone := cyphers.Group("/menezes-vanstone")
+			{
+				menezesVanstone.POST("/encrypt", canEncrypt, app.encryptMenezesVanstone)
+				menezesVanstone.POST("/decrypt", canDecrypt, app.decryptMenezesVanstone)
+			}
+		}
+
+		keys := api.Group("/keys", app.limitAddress, app.authenticate, app.rateLimit)
+		{
+			keys.POST("", canGenerate, app.createStoredKey)
+			keys.GET("", canRead, app.listStoredKeys)
+			keys.GET("/:id", canRead, app.getStoredKey)
+			keys.DELETE("/:id", canDelete, app.deleteStoredKey)
+		}
+
+		v2 := api.Group("/v2", app.limitAddress, app.authenticate, app.rateLimit)
+		{
+			v2.POST("/encrypt", canEncrypt, app.encryptV2)
+			v2.POST("/decrypt", canDecrypt, app.decryptV2)
+			v2.POST("/keys", canGenerate, app.generateKeyV2)
+			v2.POST("/inspect", canRead, app.inspectV2)
+		}
+
+		toy := api.Group("/toy", app.limitAddress, app.authenticate, app.rateLimit, app.require(auth.ScopeToy))
+		{
+			toy.GET("/points", app.toyPoints)
+			toy.GET("/group-order", app.toyGroupOrder)
+			toy.POST("/add", app.toyAdd)
+			toy.POST("/double", app.toyDouble)
+			toy.POST("/multiply", app.toyMultiply)
+			toy.POST("/order", app.toyOrder)
+			toy.POST("/dlog", app.toyDiscreteLog)
 		}
 	}
 
 	router.Use(ginzerolog.Logger("gin"))
 
-	return router
+	return router, nil
 }
 
 func (app *App) swagger(c *gin.Context) {
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"github.com/axidex/elliptic/internal/auth"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// principalKey is the gin context key of the authenticated *auth.Principal.
+const principalKey = "principal"
+
+// authenticate identifies the client and rejects the request with 401 if it
+// has no valid credentials. It does nothing when auth is disabled.
+func (app *App) authenticate(c *gin.Context) {
+	if !app.config.Auth.Enabled {
+		return
+	}
+
+	principal, err := app.auth.Authenticate(c.Request)
+	if err != nil {
+		app.logger.Warnf("Authentication failed for %s: %v", c.ClientIP(), err)
+		c.Header("WWW-Authenticate", "Bearer")
+		fail(c, http.StatusUnauthorized, CodeUnauthorized, "unauthorized")
+		return
+	}
+	c.Set(principalKey, principal)
+}
+
+// clientID names the client of a request: the authenticated principal, or
+// the client IP when auth is disabled.
+func clientID(c *gin.Context) string {
+	if principal, ok := c.Get(principalKey); ok {
+		return principal.(*auth.Principal).Name
+	}
+	return c.ClientIP()
+}
+
+// require rejects the request with 403 unless the client was granted scope.
+func (app *App) require(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !app.config.Auth.Enabled {
+			return
+		}
+
+		principal := c.MustGet(principalKey).(*auth.Principal)
+		if !principal.HasScope(scope) {
+			app.logger.Warnf("Client %s lacks scope %s", principal.Name, scope)
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Code: CodeForbidden, Error: "forbidden", Scope: scope})
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"slices"
+)
+
+// encodings lists the formats keys and ciphertexts are exchanged in.
+var encodings = []EncodingCapability{
+	{Name: "pem", Description: "Keys as PEM blocks: ELLIPTIC CURVE PUBLIC KEY and ELLIPTIC CURVE PRIVATE KEY with the suite OIDs of SEC 1, C.5"},
+	{Name: "base64", Description: "Ciphertexts of the text endpoints, standard base64 with padding"},
+	{Name: "binary", Description: "Ciphertexts of the file endpoints, raw bytes"},
+	{Name: "hex", Description: "Intermediate values in explained responses"},
+}
+
+// schemes lists the encryption schemes on top of the curve keys, with the
+// limits clients have to know about.
+var schemes = []SchemeCapability{
+	{Name: "ecies", Description: "ECIES with the suite of the key: ECDH, concatenation KDF, AES-CTR and HMAC"},
+	{Name: "hybrid", Description: "ECIES combined with ML-KEM-768, secure as long as either of them holds"},
+	{Name: "threshold", Description: "ECIES decryption by any threshold of Shamir shares of the private key"},
+	{
+		Name:        "proxy-reencryption",
+		Description: "Re-encryption of ECIES ciphertexts for a delegatee by a proxy holding a re-encryption key",
+		Caveats:     "Not collusion-safe: the re-encryption key together with the delegatee private key reveals the delegator private key",
+	},
+	{Name: "elgamal", Description: "EC-ElGamal with Koblitz encoding of the message", Caveats: "Ciphertexts are not authenticated, for comparison only"},
+	{Name: "menezes-vanstone", Description: "Menezes–Vanstone with the message masked by the shared point", Caveats: "Malleable and leaks under known plaintext, for comparison only"},
+}
+
+// capabilities collects the curves and suites registered in the cypher
+// package with the schemes, KDFs and encodings they are used with.
+func capabilities() Capabilities {
+	suites := cypher.Suites()
+
+	var hashes []string
+	result := Capabilities{Schemes: schemes, Encodings: encodings}
+	for _, suite := range suites {
+		result.Suites = append(result.Suites, SuiteCapability{
+			Name:         suite.Name,
+			Description:  suite.Description,
+			Cipher:       fmt.Sprintf("AES-%d-CTR", suite.Params.KeyLen*8),
+			MAC:          "HMAC-" + suite.HashName(),
+			KDF:          "concat-kdf-" + suite.HashName(),
+			KeyBits:      suite.Params.KeyLen * 8,
+			MacBits:      suite.Params.MacLen * 8,
+			SecurityBits: suite.SecurityBits(),
+		})
+		if !slices.Contains(hashes, suite.HashName()) {
+			hashes = append(hashes, suite.HashName())
+		}
+	}
+
+	for _, curve := range cypher.Curves() {
+		supported := []string{}
+		for _, suite := range suites {
+			if curve.Supports(suite) {
+				supported = append(supported, suite.Name)
+			}
+		}
+		result.Curves = append(result.Curves, CurveCapability{
+			Name:         curve.Name,
+			OID:          curve.OID.String(),
+			Bits:         curve.Curve.Params().BitSize,
+			SecurityBits: curve.SecurityBits(),
+			DefaultSuite: curve.DefaultSuite,
+			Suites:       supported,
+		})
+	}
+
+	result.KDFs = []KDFCapability{
+		{Name: "concat-kdf", Description: "NIST SP 800-56 concatenation KDF, derives the ECIES cipher and MAC keys", Hashes: hashes},
+		{Name: "hkdf", Description: "RFC 5869 HKDF, derives keys from /agree and the authenticated key exchange", Hashes: hashes},
+	}
+	return result
+}
+
+// @Summary List capabilities
+// @Description List the supported curves with the suites usable on them, the suites, encryption schemes with their caveats, KDFs and encodings together with their security levels in bits
+// @Tags keys
+// @Produce json
+// @Success 200 {object} Capabilities
+// @Router /api/cypher/capabilities [get]
+func (app *App) capabilities(c *gin.Context) {
+	c.JSON(http.StatusOK, capabilities())
+}
@@ -2,20 +2,45 @@ package api
 
 import (
 	"crypto/rand"
+	"encoding/hex"
+	"github.com/axidex/elliptic/internal/audit"
 	"github.com/axidex/elliptic/internal/cypher"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
 )
 
+// explained answers with the result and the recorded steps when the request
+// asked for ?explain=true, and with the plain result otherwise.
+func explained(c *gin.Context, result string, tr *cypher.Trace) {
+	if tr == nil {
+		c.String(http.StatusOK, result)
+		return
+	}
+
+	steps := make([]TraceStep, 0, len(tr.Steps))
+	for _, step := range tr.Steps {
+		steps = append(steps, TraceStep{
+			Name:   step.Name,
+			Value:  hex.EncodeToString(step.Value),
+			Secret: step.Secret,
+		})
+	}
+	c.JSON(http.StatusOK, ExplainedResult{Result: result, Steps: steps})
+}
+
 // @Summary Encrypt data
-// @Description Encrypt the provided text using the given public key
+// @Description Encrypt the provided text using the given public key or the stored key with the given ID
 // @Tags encryption
 // @Accept application/json
-// @Produce text/plain
+// @Produce plain,json
 // @Param payload body EncryptRequest true "Payload"
+// @Param explain query bool false "Return an ExplainedResult JSON with the intermediate values instead of plain text"
 // @Success 200 {string} string "Encrypted data"
-// @Failure 400 {object} map[string]any
-// @Failure 500 {object} map[string]any
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
 // @Router /api/cypher/elliptic/encrypt [post]
 func (app *App) encrypt(c *gin.Context) {
 	var req EncryptRequest
@@ -23,46 +48,63 @@ func (app *App) encrypt(c *gin.Context) {
 	// Попытка привязки данных из JSON тела
 	if err := c.ShouldBindJSON(&req); err != nil {
 		app.logger.Warnf("Invalid input: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
 		return
 	}
 
 	// Проверка текста
 	if len(req.Text) == 0 {
 		app.logger.Warnf("Provided text is empty")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "text is empty"})
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "text is empty")
+		return
+	}
+
+	if !app.checkPlaintext(c, len(req.Text)) {
 		return
 	}
 
 	// 	Public string `json:"public"  form:"public"`
 	app.logger.Infof("Got task encryption")
-	app.logger.Infof("Creating public key from user input")
-	key, err := cypher.ImportPublicPEM([]byte(req.PEMKey))
-	if err != nil {
-		app.logger.Infof("Not valid key: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provide valid key"})
+	op := app.operation(c, audit.ActionEncrypt, "encrypt", len(req.Text))
+	key, ok := app.requestPublicKey(c, req.PEMKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
 		return
 	}
-
-	encryptedText, err := cypher.Encrypt(rand.Reader, key, []byte(req.Text), nil, nil)
+	op.Key(key)
+
+	var (
+		encryptedText string
+		trace         *cypher.Trace
+		err           error
+	)
+	if explain, _ := strconv.ParseBool(c.Query("explain")); explain {
+		encryptedText, trace, err = cypher.EncryptTrace(rand.Reader, key, []byte(req.Text), nil, nil)
+	} else {
+		encryptedText, err = cypher.EncryptContext(c.Request.Context(), rand.Reader, key, []byte(req.Text), nil, nil)
+	}
+	op.Done(err)
 	if err != nil {
 		app.logger.Infof("Encryption error %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "encryption error"})
+		failCypher(c, err, "encryption error")
 		return
 	}
 
-	c.String(http.StatusOK, encryptedText)
+	explained(c, encryptedText, trace)
 }
 
 // @Summary Decrypt data
-// @Description Decrypt the provided text using the given public key
+// @Description Decrypt the provided text using the given private key or, in KMS mode, only the stored key with the given ID
 // @Tags encryption
 // @Accept application/json
-// @Produce text/plain
+// @Produce plain,json
 // @Param payload body EncryptRequest true "Payload"
+// @Param explain query bool false "Return an ExplainedResult JSON with the intermediate values instead of plain text"
 // @Success 200 {string} string "Decrypted data"
-// @Failure 400 {object} map[string]any
-// @Failure 500 {object} map[string]any
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /api/cypher/elliptic/decrypt [post]
 func (app *App) decrypt(c *gin.Context) {
 	var req EncryptRequest
@@ -70,69 +112,99 @@ func (app *App) decrypt(c *gin.Context) {
 	// Попытка привязки данных из JSON тела
 	if err := c.ShouldBindJSON(&req); err != nil {
 		app.logger.Warnf("Invalid input: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
 		return
 	}
 
 	// Проверка текста
 	if len(req.Text) == 0 {
 		app.logger.Warnf("Provided text is empty")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "text is empty"})
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "text is empty")
 		return
 	}
 
 	app.logger.Infof("Got task decryption")
 
-	key, err := cypher.ImportPrivatePEM([]byte(req.PEMKey))
-	if err != nil {
-		app.logger.Infof("Not valid key: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provide valid key"})
+	op := app.operation(c, audit.ActionDecrypt, "decrypt", len(req.Text))
+	key, ok := app.requestPrivateKey(c, req.PEMKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
 		return
 	}
+	op.Key(&key.PublicKey)
 
 	//app.logger.Infof("Decrypting data %s", encryptedBytes)
 
-	decryptText, err := key.Decrypt(rand.Reader, req.Text, nil, nil)
+	var (
+		decryptText []byte
+		trace       *cypher.Trace
+		err         error
+	)
+	if explain, _ := strconv.ParseBool(c.Query("explain")); explain {
+		decryptText, trace, err = key.DecryptTrace(rand.Reader, req.Text, nil, nil)
+	} else {
+		decryptText, err = key.DecryptContext(c.Request.Context(), rand.Reader, req.Text, nil, nil)
+	}
+	op.Done(err)
 	if err != nil {
 		app.logger.Infof("Decryption error %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "decryption error"})
+		failDecryption(c)
 		return
 	}
 
-	c.String(http.StatusOK, string(decryptText))
+	explained(c, string(decryptText), trace)
 }
 
 // @Summary Generate a public key
-// @Description Generate a public key using the elliptic curve algorithm
+// @Description Generate a keypair using the elliptic curve algorithm. In KMS mode the private key is kept on the server and its ID is returned instead. See /api/cypher/capabilities for the curves and suites
 // @Tags keys
 // @Accept json
 // @Produce json
+// @Param curve query string false "Curve name" default(P-256)
+// @Param suite query string false "Suite name, the curve's default suite if empty"
 // @Success 200 {object} Keys
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /api/cypher/elliptic/keys [get]
 func (app *App) generateKey(c *gin.Context) {
-	keys, err := cypher.GenerateKey(rand.Reader, cypher.DefaultCurve, nil)
+	curve, suite, ok := keyParams(c.Query("curve"), c.Query("suite"))
+	if !ok {
+		app.logger.Warnf("Unsupported curve %q or suite %q", c.Query("curve"), c.Query("suite"))
+		fail(c, http.StatusBadRequest, CodeUnsupportedParameters, "unsupported curve or suite")
+		return
+	}
+
+	op := app.operation(c, audit.ActionGenerateKey, "generate_key", 0)
+	keys, err := cypher.GenerateKey(rand.Reader, curve.Curve, suite.Params)
+	if err == nil {
+		op.Key(&keys.PublicKey)
+	}
+	op.Done(err)
 	if err != nil {
 		app.logger.Errorf("GenerateKey err: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "generating keys error"})
+		failCypher(c, err, "generating keys error")
+		return
+	}
+
+	if app.config.KMS.Enabled {
+		app.storeGeneratedKey(c, keys)
 		return
 	}
 
 	private, err := cypher.ExportPrivatePEM(keys)
 	if err != nil {
 		app.logger.Errorf("Encoding err: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "encoding keys error"})
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
 		return
 	}
 
 	public, err := cypher.ExportPublicPEM(&keys.PublicKey)
 	if err != nil {
 		app.logger.Errorf("Encoding err: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "encoding keys error"})
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
 		return
 	}
 
-	app.logger.Info("private key: ", keys.D, "\tpublic key: ", keys.PublicKey)
-
 	c.JSON(http.StatusOK, Keys{
 		Private: string(private),
 		Public:  string(public),
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Generate a data key
+// @Description Generate a random AES key and return it in plaintext and wrapped with ECIES under the given public key or stored key
+// @Tags datakey
+// @Accept application/json
+// @Produce json
+// @Param payload body DataKeyRequest true "Payload"
+// @Success 200 {object} DataKey
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/datakey [post]
+func (app *App) generateDataKey(c *gin.Context) {
+	var req DataKeyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+	if req.Size == 0 {
+		req.Size = 32
+	}
+
+	app.logger.Infof("Got task data key generation")
+
+	op := app.operation(c, audit.ActionEncrypt, "generate_data_key", req.Size)
+	key, ok := app.requestPublicKey(c, req.PEMKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(key)
+
+	plaintext, wrapped, err := cypher.GenerateDataKey(rand.Reader, key, req.Size)
+	op.Done(err)
+	if errors.Is(err, cypher.ErrInvalidKeyLength) {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "size must be 16, 24 or 32")
+		return
+	} else if err != nil {
+		app.logger.Infof("Data key error %v", err)
+		failCypher(c, err, "encryption error")
+		return
+	}
+
+	c.JSON(http.StatusOK, DataKey{
+		Plaintext: base64.StdEncoding.EncodeToString(plaintext),
+		Wrapped:   wrapped,
+	})
+}
+
+// @Summary Unwrap a data key
+// @Description Decrypt a wrapped data key with the given private key or, in KMS mode, only the stored key with the given ID
+// @Tags datakey
+// @Accept application/json
+// @Produce json
+// @Param payload body UnwrapDataKeyRequest true "Payload"
+// @Success 200 {object} DataKey
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/datakey/unwrap [post]
+func (app *App) unwrapDataKey(c *gin.Context) {
+	var req UnwrapDataKeyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	app.logger.Infof("Got task data key unwrapping")
+
+	op := app.operation(c, audit.ActionDecrypt, "unwrap_data_key", len(req.Wrapped))
+	key, ok := app.requestPrivateKey(c, req.PEMKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(&key.PublicKey)
+
+	plaintext, err := key.UnwrapDataKey(rand.Reader, req.Wrapped)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Decryption error %v", err)
+		failDecryption(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, DataKey{
+		Plaintext: base64.StdEncoding.EncodeToString(plaintext),
+	})
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// Error codes of ErrorResponse. They are part of the API: clients may match
+// on them, so existing codes must not change.
+const (
+	CodeInvalidRequest        = "invalid_request"
+	CodeInvalidKey            = "invalid_key"
+	CodeUnsupportedParameters = "unsupported_parameters"
+	CodeMessageTooLong        = "message_too_long"
+	CodeDecryptionFailed      = "decryption_failed"
+	CodeAlreadyReencrypted    = "already_reencrypted"
+	CodeInvalidThreshold      = "invalid_threshold"
+	CodeInvalidShare          = "invalid_share"
+	CodeNotEnoughShares       = "not_enough_shares"
+	CodeHandshakeFailed       = "handshake_failed"
+	CodeUnknownPeer           = "unknown_peer"
+	CodeNoSolution            = "no_solution"
+	CodeUnauthorized          = "unauthorized"
+	CodeForbidden             = "forbidden"
+	CodeNotFound              = "not_found"
+	CodePayloadTooLarge       = "payload_too_large"
+	CodeUnsupportedMediaType  = "unsupported_media_type"
+	CodeRateLimited           = "rate_limited"
+	CodeInternal              = "internal_error"
+)
+
+// decryptionFailed is the only answer to a failed decryption.
+const decryptionFailed = "decryption failed"
+
+// cypherErrors maps the errors of the cypher package to responses. Messages
+// are fixed, so error details never reach the client.
+var cypherErrors = []struct {
+	err     error
+	status  int
+	code    string
+	message string
+}{
+	{cypher.ErrInvalidMessage, http.StatusBadRequest, CodeDecryptionFailed, decryptionFailed},
+	{cypher.ErrSharedKeyIsPointAtInfinity, http.StatusBadRequest, CodeDecryptionFailed, decryptionFailed},
+	{cypher.ErrImport, http.StatusBadRequest, CodeInvalidKey, "provide valid key"},
+	{cypher.ErrInvalidPublicKey, http.StatusBadRequest, CodeInvalidKey, "provide valid key"},
+	{cypher.ErrInvalidPrivateKey, http.StatusBadRequest, CodeInvalidKey, "provide valid key"},
+	{cypher.ErrInvalidCurve, http.StatusBadRequest, CodeInvalidKey, "keys must share curve and parameters"},
+	{cypher.ErrInvalidParams, http.StatusBadRequest, CodeInvalidKey, "keys must share curve and parameters"},
+	{cypher.ErrInvalidHybridKey, http.StatusBadRequest, CodeInvalidKey, "provide valid hybrid key"},
+	{cypher.ErrInvalidReencryptionKey, http.StatusBadRequest, CodeInvalidKey, "provide valid re-encryption key"},
+	{cypher.ErrUnsupportedECIESParameters, http.StatusBadRequest, CodeUnsupportedParameters, "unsupported ECIES parameters"},
+	{cypher.ErrUnsupportedECDHAlgorithm, http.StatusBadRequest, CodeUnsupportedParameters, "unsupported ECDH algorithm"},
+	{cypher.ErrSharedKeyTooBig, http.StatusBadRequest, CodeUnsupportedParameters, "suite is too strong for the curve"},
+	{cypher.ErrKeyDataTooLong, http.StatusBadRequest, CodeUnsupportedParameters, "requested key is too long"},
+	{cypher.ErrSharedTooLong, http.StatusBadRequest, CodeUnsupportedParameters, "requested key is too long"},
+	{cypher.ErrInvalidKeyLength, http.StatusBadRequest, CodeInvalidRequest, "invalid key length"},
+	{cypher.ErrEmptyMessage, http.StatusBadRequest, CodeInvalidRequest, "plaintext is empty"},
+	{cypher.ErrMessageEncoding, http.StatusBadRequest, CodeMessageTooLong, "message can't be encoded as a curve point"},
+	{cypher.ErrAlreadyReencrypted, http.StatusConflict, CodeAlreadyReencrypted, "ciphertext is already re-encrypted"},
+	{cypher.ErrInvalidThreshold, http.StatusBadRequest, CodeInvalidThreshold, "invalid threshold"},
+	{cypher.ErrInvalidKeyShare, http.StatusBadRequest, CodeInvalidShare, "provide valid share"},
+	{cypher.ErrInvalidDecryptPart, http.StatusBadRequest, CodeInvalidShare, "provide valid share"},
+	{cypher.ErrInvalidShareCurve, http.StatusBadRequest, CodeInvalidShare, "shares must use the key's curve"},
+	{cypher.ErrDuplicateShare, http.StatusBadRequest, CodeInvalidShare, "duplicate share"},
+	{cypher.ErrNotEnoughShares, http.StatusBadRequest, CodeNotEnoughShares, "not enough shares"},
+	{cypher.ErrHandshakeState, http.StatusBadRequest, CodeHandshakeFailed, "handshake failed"},
+	{cypher.ErrHandshakeFailed, http.StatusBadRequest, CodeHandshakeFailed, "handshake failed"},
+	{cypher.ErrUnknownPeer, http.StatusForbidden, CodeUnknownPeer, "peer is not trusted"},
+	// Registry errors only happen at startup.
+	{cypher.ErrCurveRegistered, http.StatusInternalServerError, CodeInternal, "internal error"},
+	{cypher.ErrSuiteRegistered, http.StatusInternalServerError, CodeInternal, "internal error"},
+	{cypher.ErrUnknownSuite, http.StatusInternalServerError, CodeInternal, "internal error"},
+}
+
+// fail answers with an ErrorResponse and stops the handler chain.
+func fail(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, ErrorResponse{Code: code, Error: message})
+}
+
+// failCypher answers with the response mapped from an error of the cypher
+// package. Other errors are internal errors, described by message.
+func failCypher(c *gin.Context, err error, message string) {
+	for _, known := range cypherErrors {
+		if errors.Is(err, known.err) {
+			fail(c, known.status, known.code, known.message)
+			return
+		}
+	}
+	fail(c, http.StatusInternalServerError, CodeInternal, message)
+}
+
+// failDecryption answers a failed decryption. Whatever went wrong - a bad
+// MAC, a malformed ciphertext or an invalid ephemeral key - the response is
+// the same, so that it can't be used as a decryption oracle.
+func failDecryption(c *gin.Context) {
+	fail(c, http.StatusBadRequest, CodeDecryptionFailed, decryptionFailed)
+}
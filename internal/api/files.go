@@ -0,0 +1,209 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/gin-gonic/gin"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	mimeOctetStream = "application/octet-stream"
+	mimeMultipart   = "multipart/form-data"
+
+	// encryptedSuffix is appended to the names of encrypted downloads.
+	encryptedSuffix = ".enc"
+)
+
+var errUnsupportedMedia = errors.New("unsupported content type")
+
+// upload is the data and key of a file request.
+type upload struct {
+	name   string
+	data   []byte
+	pemKey string
+	keyID  string
+}
+
+// readUpload reads a file request. A multipart form carries the data in the
+// "file" part and the key either as a "pemKey" file or field or as a "keyId"
+// field. A raw octet-stream body carries only the data; the stored key is
+// selected with the keyId query parameter and the name with filename.
+func readUpload(c *gin.Context) (*upload, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	switch mediaType {
+	case mimeMultipart:
+		file, err := c.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		data, err := readFormFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		up := &upload{name: file.Filename, data: data, keyID: c.PostForm("keyId")}
+		if key, err := c.FormFile("pemKey"); err == nil {
+			pemKey, err := readFormFile(key)
+			if err != nil {
+				return nil, err
+			}
+			up.pemKey = string(pemKey)
+		} else {
+			up.pemKey = c.PostForm("pemKey")
+		}
+		return up, nil
+	case mimeOctetStream:
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &upload{name: c.Query("filename"), data: data, keyID: c.Query("keyId")}, nil
+	default:
+		return nil, errUnsupportedMedia
+	}
+}
+
+func readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// bindUpload reads a file request and answers 415 or 400 if it can't.
+func (app *App) bindUpload(c *gin.Context) (*upload, bool) {
+	up, err := readUpload(c)
+	if errors.Is(err, errUnsupportedMedia) {
+		fail(c, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, "use multipart/form-data or application/octet-stream")
+		return nil, false
+	} else if err != nil {
+		app.logger.Warnf("Invalid upload: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return nil, false
+	}
+	if len(up.data) == 0 {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "file is empty")
+		return nil, false
+	}
+	return up, true
+}
+
+// download sends data as a binary file attachment.
+func download(c *gin.Context, name string, data []byte) {
+	name = filepath.Base(name)
+	if name == "." || name == string(filepath.Separator) {
+		name = "data"
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Data(http.StatusOK, mimeOctetStream, data)
+}
+
+// @Summary Encrypt a file
+// @Description Encrypt a binary file with the given public key or stored key. Send a multipart form with the data in "file" and the key in "pemKey" or "keyId", or the raw data as application/octet-stream with keyId in the query. The binary ciphertext is returned as a download
+// @Tags files
+// @Accept multipart/form-data,octet-stream
+// @Produce octet-stream
+// @Param file formData file false "Data to encrypt"
+// @Param pemKey formData file false "PEM public key"
+// @Param keyId formData string false "Stored key ID"
+// @Param keyId query string false "Stored key ID for octet-stream bodies"
+// @Param filename query string false "File name for octet-stream bodies"
+// @Success 200 {file} file "Encrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/encrypt/file [post]
+func (app *App) encryptFile(c *gin.Context) {
+	up, ok := app.bindUpload(c)
+	if !ok {
+		return
+	}
+	if !app.checkPlaintext(c, len(up.data)) {
+		return
+	}
+
+	app.logger.Infof("Got task file encryption")
+	op := app.operation(c, audit.ActionEncrypt, "encrypt_file", len(up.data))
+	key, ok := app.requestPublicKey(c, up.pemKey, up.keyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(key)
+
+	ct, err := cypher.EncryptRaw(c.Request.Context(), rand.Reader, key, up.data, nil, nil)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Encryption error %v", err)
+		failCypher(c, err, "encryption error")
+		return
+	}
+
+	name := up.name
+	if name == "" {
+		name = "data"
+	}
+	download(c, name+encryptedSuffix, ct)
+}
+
+// @Summary Decrypt a file
+// @Description Decrypt a binary ciphertext produced by /encrypt/file with the given private key or, in KMS mode, only the stored key. The request has the same forms as /encrypt/file and the plaintext is returned as a download
+// @Tags files
+// @Accept multipart/form-data,octet-stream
+// @Produce octet-stream
+// @Param file formData file false "Data to decrypt"
+// @Param pemKey formData file false "PEM private key"
+// @Param keyId formData string false "Stored key ID"
+// @Param keyId query string false "Stored key ID for octet-stream bodies"
+// @Param filename query string false "File name for octet-stream bodies"
+// @Success 200 {file} file "Decrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/decrypt/file [post]
+func (app *App) decryptFile(c *gin.Context) {
+	up, ok := app.bindUpload(c)
+	if !ok {
+		return
+	}
+
+	app.logger.Infof("Got task file decryption")
+	op := app.operation(c, audit.ActionDecrypt, "decrypt_file", len(up.data))
+	key, ok := app.requestPrivateKey(c, up.pemKey, up.keyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(&key.PublicKey)
+
+	m, err := key.DecryptRaw(c.Request.Context(), rand.Reader, up.data, nil, nil)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Decryption error %v", err)
+		failDecryption(c)
+		return
+	}
+
+	name := strings.TrimSuffix(up.name, encryptedSuffix)
+	switch {
+	case up.name == "":
+		name = "data"
+	case name == up.name:
+		name += ".dec"
+	}
+	download(c, name, m)
+}
@@ -8,3 +8,19 @@ import (
 func (app *App) health(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "OK"})
 }
+
+// ready reports whether the server accepts new work. It fails once the
+// server has started draining, so load balancers stop routing to it.
+func (app *App) ready(c *gin.Context) {
+	if app.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "OK"})
+}
+
+// Drain marks the server as shutting down for the readiness check.
+func (app *App) Drain() {
+	app.draining.Store(true)
+	app.logger.Infof("Draining, readiness check is failing now")
+}
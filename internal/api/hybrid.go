@@ -0,0 +1,142 @@
+package api
+
+import (
+	"crypto/rand"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Encrypt data with a hybrid key
+// @Description Encrypt the provided text using the given hybrid ECDH + ML-KEM-768 public key or the stored hybrid key with the given ID
+// @Tags hybrid
+// @Accept application/json
+// @Produce text/plain
+// @Param payload body EncryptRequest true "Payload"
+// @Success 200 {string} string "Encrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /api/cypher/elliptic/hybrid/encrypt [post]
+func (app *App) encryptHybrid(c *gin.Context) {
+	var req EncryptRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	if !app.checkPlaintext(c, len(req.Text)) {
+		return
+	}
+
+	app.logger.Infof("Got task hybrid encryption")
+	op := app.operation(c, audit.ActionEncrypt, "hybrid_encrypt", len(req.Text))
+	key, ok := app.requestHybridPublicKey(c, req.PEMKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(&key.PublicKey)
+
+	encryptedText, err := cypher.EncryptHybrid(rand.Reader, key, []byte(req.Text), nil, nil)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Encryption error %v", err)
+		failCypher(c, err, "encryption error")
+		return
+	}
+
+	c.String(http.StatusOK, encryptedText)
+}
+
+// @Summary Decrypt data with a hybrid key
+// @Description Decrypt the provided text using the given hybrid ECDH + ML-KEM-768 private key or, in KMS mode, only the stored hybrid key with the given ID
+// @Tags hybrid
+// @Accept application/json
+// @Produce text/plain
+// @Param payload body EncryptRequest true "Payload"
+// @Success 200 {string} string "Decrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/hybrid/decrypt [post]
+func (app *App) decryptHybrid(c *gin.Context) {
+	var req EncryptRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	app.logger.Infof("Got task hybrid decryption")
+
+	op := app.operation(c, audit.ActionDecrypt, "hybrid_decrypt", len(req.Text))
+	key, ok := app.requestHybridPrivateKey(c, req.PEMKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(&key.PublicKey)
+
+	decryptText, err := key.Decrypt(rand.Reader, req.Text, nil, nil)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Decryption error %v", err)
+		failDecryption(c)
+		return
+	}
+
+	c.String(http.StatusOK, string(decryptText))
+}
+
+// @Summary Generate a hybrid key pair
+// @Description Generate an elliptic curve key pair combined with an ML-KEM-768 key pair. In KMS mode the private key is kept on the server and its ID is returned instead
+// @Tags hybrid
+// @Accept json
+// @Produce json
+// @Success 200 {object} Keys
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/hybrid/keys [get]
+func (app *App) generateHybridKey(c *gin.Context) {
+	op := app.operation(c, audit.ActionGenerateKey, "hybrid_generate_key", 0)
+	keys, err := cypher.GenerateHybridKey(rand.Reader, cypher.DefaultCurve, nil)
+	if err == nil {
+		op.Key(&keys.PublicKey)
+	}
+	op.Done(err)
+	if err != nil {
+		app.logger.Errorf("GenerateHybridKey err: %s", err)
+		failCypher(c, err, "generating keys error")
+		return
+	}
+
+	if app.config.KMS.Enabled {
+		app.storeGeneratedHybridKey(c, keys)
+		return
+	}
+
+	private, err := cypher.ExportHybridPrivatePEM(keys)
+	if err != nil {
+		app.logger.Errorf("Encoding err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+		return
+	}
+
+	public, err := cypher.ExportHybridPublicPEM(keys.Public())
+	if err != nil {
+		app.logger.Errorf("Encoding err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+		return
+	}
+
+	c.JSON(http.StatusOK, Keys{
+		Private: string(private),
+		Public:  string(public),
+	})
+}
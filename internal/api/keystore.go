@@ -0,0 +1,391 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/axidex/elliptic/internal/keystore"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// keyParams resolves the curve and suite names of a key request, falling
+// back to the default curve and its default suite. It fails if a name is
+// unknown or the suite can't be used with the curve.
+func keyParams(curveName, suiteName string) (cypher.Curve, cypher.Suite, bool) {
+	if curveName == "" {
+		curveName = cypher.DefaultCurve.Params().Name
+	}
+	curve, ok := cypher.CurveByName(curveName)
+	if !ok {
+		return cypher.Curve{}, cypher.Suite{}, false
+	}
+	if suiteName == "" {
+		suiteName = curve.DefaultSuite
+	}
+	suite, ok := cypher.SuiteByName(suiteName)
+	if !ok || !curve.Supports(suite) {
+		return cypher.Curve{}, cypher.Suite{}, false
+	}
+	return curve, suite, true
+}
+
+func storedKey(key keystore.Key) (StoredKey, error) {
+	var (
+		public []byte
+		err    error
+	)
+	if key.Hybrid() {
+		var prv *cypher.HybridPrivateKey
+		if prv, err = key.HybridPrivateKey(); err == nil {
+			public, err = cypher.ExportHybridPublicPEM(prv.Public())
+		}
+	} else {
+		var pub *cypher.PublicKey
+		if pub, err = key.PublicKey(); err == nil {
+			public, err = cypher.ExportPublicPEM(pub)
+		}
+	}
+	if err != nil {
+		return StoredKey{}, err
+	}
+	return StoredKey{
+		ID:        key.ID,
+		Curve:     key.Curve,
+		Suite:     key.Suite,
+		CreatedAt: key.CreatedAt,
+		Public:    string(public),
+	}, nil
+}
+
+// @Summary Generate a stored key
+// @Description Generate a keypair on the server and keep the private key in the key store
+// @Tags keystore
+// @Accept application/json
+// @Produce json
+// @Param payload body CreateKeyRequest false "Payload"
+// @Success 201 {object} StoredKey
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/keys [post]
+func (app *App) createStoredKey(c *gin.Context) {
+	var req CreateKeyRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			app.logger.Warnf("Invalid input: %v", err)
+			fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+			return
+		}
+	}
+
+	curve, suite, ok := keyParams(req.Curve, req.Suite)
+	if !ok {
+		app.logger.Warnf("Unsupported curve %q or suite %q", req.Curve, req.Suite)
+		fail(c, http.StatusBadRequest, CodeUnsupportedParameters, "unsupported curve or suite")
+		return
+	}
+
+	op := app.operation(c, audit.ActionGenerateKey, "generate_stored_key", 0)
+	prv, err := cypher.GenerateKey(rand.Reader, curve.Curve, suite.Params)
+	if err != nil {
+		op.Done(err)
+		app.logger.Errorf("GenerateKey err: %s", err)
+		failCypher(c, err, "generating keys error")
+		return
+	}
+	op.Key(&prv.PublicKey)
+
+	key, err := keystore.NewKey(prv)
+	if err == nil {
+		op.Detail("key_id", key.ID)
+		err = app.keys.Put(c.Request.Context(), key)
+	}
+	op.Done(err)
+	if err != nil {
+		app.logger.Errorf("Key store err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "storing key error")
+		return
+	}
+
+	result, err := storedKey(key)
+	if err != nil {
+		app.logger.Errorf("Encoding err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+		return
+	}
+
+	app.logger.Infof("Stored key %s", key.ID)
+	c.JSON(http.StatusCreated, result)
+}
+
+// @Summary List stored keys
+// @Description List the keys in the key store with their public halves
+// @Tags keystore
+// @Produce json
+// @Success 200 {array} StoredKey
+// @Failure 500 {object} ErrorResponse
+// @Router /api/keys [get]
+func (app *App) listStoredKeys(c *gin.Context) {
+	keys, err := app.keys.List(c.Request.Context())
+	if err != nil {
+		app.logger.Errorf("Key store err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "key store error")
+		return
+	}
+
+	result := make([]StoredKey, 0, len(keys))
+	for _, key := range keys {
+		stored, err := storedKey(key)
+		if err != nil {
+			app.logger.Errorf("Encoding err: %s", err)
+			fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+			return
+		}
+		result = append(result, stored)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Get a stored key
+// @Description Get the public half of a stored key
+// @Tags keystore
+// @Produce json
+// @Param id path string true "Key ID"
+// @Success 200 {object} StoredKey
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/keys/{id} [get]
+func (app *App) getStoredKey(c *gin.Context) {
+	key, err := app.keys.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, keystore.ErrNotFound) {
+		fail(c, http.StatusNotFound, CodeNotFound, "key not found")
+		return
+	} else if err != nil {
+		app.logger.Errorf("Key store err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "key store error")
+		return
+	}
+
+	result, err := storedKey(key)
+	if err != nil {
+		app.logger.Errorf("Encoding err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Delete a stored key
+// @Description Delete a key from the key store
+// @Tags keystore
+// @Param id path string true "Key ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/keys/{id} [delete]
+func (app *App) deleteStoredKey(c *gin.Context) {
+	err := app.keys.Delete(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, keystore.ErrNotFound) {
+		fail(c, http.StatusNotFound, CodeNotFound, "key not found")
+		return
+	} else if err != nil {
+		app.logger.Errorf("Key store err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "key store error")
+		return
+	}
+
+	app.logger.Infof("Deleted key %s", c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+// requestPublicKey resolves the key of a request: the public half of a
+// stored key if an ID is given, the PEM public key otherwise. On failure the
+// response is already written.
+func (app *App) requestPublicKey(c *gin.Context, pemKey, keyID string) (*cypher.PublicKey, bool) {
+	if keyID == "" {
+		_, span := tracer.Start(c.Request.Context(), "cypher.ImportPublicPEM")
+		key, err := cypher.ImportPublicPEM([]byte(pemKey))
+		span.End()
+		if err != nil {
+			app.logger.Infof("Not valid key: %v", err)
+			fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+			return nil, false
+		}
+		return key, true
+	}
+
+	prv, ok := app.storedPrivateKey(c, keyID)
+	if !ok {
+		return nil, false
+	}
+	return &prv.PublicKey, true
+}
+
+// requestPrivateKey resolves the private key of a request. In KMS mode only
+// stored keys can be used.
+func (app *App) requestPrivateKey(c *gin.Context, pemKey, keyID string) (*cypher.PrivateKey, bool) {
+	if keyID != "" {
+		return app.storedPrivateKey(c, keyID)
+	}
+	if app.rejectPrivateKey(c) {
+		return nil, false
+	}
+
+	_, span := tracer.Start(c.Request.Context(), "cypher.ImportPrivatePEM")
+	key, err := cypher.ImportPrivatePEM([]byte(pemKey))
+	span.End()
+	if err != nil {
+		app.logger.Infof("Not valid key: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+		return nil, false
+	}
+	return key, true
+}
+
+// rejectPrivateKey answers with 403 and returns true in KMS mode, where
+// private keys never cross the API.
+func (app *App) rejectPrivateKey(c *gin.Context) bool {
+	if !app.config.KMS.Enabled {
+		return false
+	}
+	app.logger.Warnf("Private key sent in KMS mode")
+	fail(c, http.StatusForbidden, CodeForbidden, "private keys are not accepted in KMS mode, provide keyId")
+	return true
+}
+
+// requestHybridPublicKey resolves the hybrid key of a request like
+// requestPublicKey.
+func (app *App) requestHybridPublicKey(c *gin.Context, pemKey, keyID string) (*cypher.HybridPublicKey, bool) {
+	if keyID == "" {
+		key, err := cypher.ImportHybridPublicPEM([]byte(pemKey))
+		if err != nil {
+			app.logger.Infof("Not valid key: %v", err)
+			fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+			return nil, false
+		}
+		return key, true
+	}
+
+	prv, ok := app.storedHybridKey(c, keyID)
+	if !ok {
+		return nil, false
+	}
+	return prv.Public(), true
+}
+
+// requestHybridPrivateKey resolves the hybrid private key of a request like
+// requestPrivateKey.
+func (app *App) requestHybridPrivateKey(c *gin.Context, pemKey, keyID string) (*cypher.HybridPrivateKey, bool) {
+	if keyID != "" {
+		return app.storedHybridKey(c, keyID)
+	}
+	if app.rejectPrivateKey(c) {
+		return nil, false
+	}
+
+	key, err := cypher.ImportHybridPrivatePEM([]byte(pemKey))
+	if err != nil {
+		app.logger.Infof("Not valid key: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+		return nil, false
+	}
+	return key, true
+}
+
+func (app *App) storedPrivateKey(c *gin.Context, id string) (*cypher.PrivateKey, bool) {
+	key, ok := app.storedKey(c, id)
+	if !ok {
+		return nil, false
+	}
+	prv, err := key.PrivateKey()
+	if err != nil {
+		app.storedKeyError(c, id, err)
+		return nil, false
+	}
+	return prv, true
+}
+
+func (app *App) storedHybridKey(c *gin.Context, id string) (*cypher.HybridPrivateKey, bool) {
+	key, ok := app.storedKey(c, id)
+	if !ok {
+		return nil, false
+	}
+	prv, err := key.HybridPrivateKey()
+	if err != nil {
+		app.storedKeyError(c, id, err)
+		return nil, false
+	}
+	return prv, true
+}
+
+func (app *App) storedKey(c *gin.Context, id string) (keystore.Key, bool) {
+	key, err := app.keys.Get(c.Request.Context(), id)
+	if errors.Is(err, keystore.ErrNotFound) {
+		fail(c, http.StatusNotFound, CodeNotFound, "key not found")
+		return keystore.Key{}, false
+	} else if err != nil {
+		app.logger.Errorf("Key store err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "key store error")
+		return keystore.Key{}, false
+	}
+	return key, true
+}
+
+// storedKeyError answers a stored key that can't be used: one of the wrong
+// type is a client error, one that can't be decoded a server error.
+func (app *App) storedKeyError(c *gin.Context, id string, err error) {
+	if errors.Is(err, keystore.ErrKeyType) {
+		fail(c, http.StatusBadRequest, CodeInvalidKey, "stored key has the wrong type for this operation")
+		return
+	}
+	app.logger.Errorf("Stored key %s err: %s", id, err)
+	fail(c, http.StatusInternalServerError, CodeInternal, "key store error")
+}
+
+// storeGeneratedKey keeps a key generated by /keys in KMS mode and answers
+// with its ID and public half only.
+func (app *App) storeGeneratedKey(c *gin.Context, prv *cypher.PrivateKey) {
+	key, err := keystore.NewKey(prv)
+	if err != nil {
+		app.logger.Errorf("Key store err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "storing key error")
+		return
+	}
+	app.putGeneratedKey(c, key)
+}
+
+// storeGeneratedHybridKey does the same as storeGeneratedKey for hybrid keys.
+func (app *App) storeGeneratedHybridKey(c *gin.Context, prv *cypher.HybridPrivateKey) {
+	key, err := keystore.NewHybridKey(prv)
+	if err != nil {
+		app.logger.Errorf("Key store err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "storing key error")
+		return
+	}
+	app.putGeneratedKey(c, key)
+}
+
+func (app *App) putGeneratedKey(c *gin.Context, key keystore.Key) {
+	if err := app.keys.Put(c.Request.Context(), key); err != nil {
+		app.logger.Errorf("Key store err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "storing key error")
+		return
+	}
+
+	stored, err := storedKey(key)
+	if err != nil {
+		app.logger.Errorf("Encoding err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+		return
+	}
+
+	app.logger.Infof("Stored key %s", key.ID)
+	c.JSON(http.StatusOK, Keys{
+		ID:     key.ID,
+		Public: stored.Public,
+	})
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"github.com/axidex/elliptic/config"
+	"github.com/gin-gonic/gin"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// limitBody rejects requests with a body larger than the configured maximum
+// with 413. The body is buffered here, so later readers such as the HMAC
+// authenticator see it unchanged.
+func (app *App) limitBody(c *gin.Context) {
+	limit := app.config.Server.MaxBodyBytes
+	if limit <= 0 || c.Request.Body == nil {
+		return
+	}
+	if c.Request.ContentLength > limit {
+		app.tooLarge(c, "request body")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+	if err != nil {
+		var tooBig *http.MaxBytesError
+		if errors.As(err, &tooBig) {
+			app.tooLarge(c, "request body")
+			return
+		}
+		app.logger.Warnf("Failed to read request body: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+}
+
+// rateLimit takes a token from the bucket of the client on the current route
+// and rejects the request with 429 when it is empty. It must run after
+// authenticate, so that authenticated clients are limited by name rather
+// than by address.
+func (app *App) rateLimit(c *gin.Context) {
+	if app.limiter == nil {
+		return
+	}
+
+	client := clientID(c)
+	ok, retryAfter := app.limiter.Allow(client, c.FullPath())
+	if !ok {
+		app.logger.Warnf("Rate limit exceeded by %s on %s", client, c.FullPath())
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		fail(c, http.StatusTooManyRequests, CodeRateLimited, "rate limit exceeded")
+		return
+	}
+}
+
+// limitAddress takes a token from the bucket of the client IP before the
+// request is authenticated, so that floods of bad credentials are limited
+// as well. It is only set up when auth is enabled; otherwise rateLimit
+// already limits by IP.
+func (app *App) limitAddress(c *gin.Context) {
+	if app.ipLimiter == nil {
+		return
+	}
+
+	ok, retryAfter := app.ipLimiter.Allow(c.ClientIP(), "")
+	if !ok {
+		app.logger.Warnf("Rate limit exceeded by address %s", c.ClientIP())
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		fail(c, http.StatusTooManyRequests, CodeRateLimited, "rate limit exceeded")
+		return
+	}
+}
+
+// ipRateLimit returns the limit of the per-IP buckets: ipRate and ipBurst,
+// or the default rate and burst when they are not set.
+func ipRateLimit(limits config.RateLimit) config.RateLimit {
+	ip := config.RateLimit{Enabled: true, Rate: limits.IPRate, Burst: limits.IPBurst}
+	if ip.Rate == 0 {
+		ip.Rate, ip.Burst = limits.Rate, limits.Burst
+	}
+	return ip
+}
+
+// checkPlaintext responds with 413 and returns false if the plaintext is
+// larger than the configured maximum.
+func (app *App) checkPlaintext(c *gin.Context, size int) bool {
+	limit := app.config.Server.MaxPlaintextBytes
+	if limit > 0 && size > limit {
+		app.tooLarge(c, "plaintext")
+		return false
+	}
+	return true
+}
+
+func (app *App) tooLarge(c *gin.Context, what string) {
+	app.logger.Warnf("Rejected %s from %s: too large", what, c.ClientIP())
+	fail(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, what+" too large")
+}
@@ -1,9 +1,17 @@
 package api
 
 import (
+	"github.com/axidex/elliptic/internal/metrics"
+	"github.com/axidex/elliptic/internal/tracing"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"time"
 )
 
+// tracer records spans of the handlers. The request span itself is started
+// by the otelgin middleware.
+var tracer = otel.Tracer("github.com/axidex/elliptic/internal/api")
+
 func (app *App) loggerMiddleware(param gin.LogFormatterParams) string {
 
 	app.logger.Infof("%s \"%s %s %s [%d] %s \"%s\" %s\"",
@@ -19,3 +27,16 @@ func (app *App) loggerMiddleware(param gin.LogFormatterParams) string {
 
 	return ""
 }
+
+func (app *App) metricsMiddleware(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+	metrics.ObserveRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+}
+
+func (app *App) serviceName() string {
+	if name := app.config.Tracing.ServiceName; name != "" {
+		return name
+	}
+	return tracing.DefaultServiceName
+}
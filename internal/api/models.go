@@ -1,15 +1,18 @@
 package api
 
+import "time"
+
 type EllipticArgs struct {
-	// Coefficients of the curve equation y^2 = x^3 + Ax + B
-	A int `json:"a" form:"a" binding:"required"`
-	B int `json:"b" form:"b" binding:"required"`
+	// Coefficients of the curve equation y^2 = x^3 + Ax + B, zero is allowed
+	A int `json:"a" form:"a"`
+	B int `json:"b" form:"b"`
 	P int `json:"p" form:"p" binding:"required"` // Prime modulus
 }
 
 type EncryptRequest struct {
 	Text   string `json:"text" binding:"required"`
-	PEMKey string `json:"pemKey" binding:"required"` // Ключ как строка
+	PEMKey string `json:"pemKey"` // Ключ как строка
+	KeyID  string `json:"keyId"`  // Или ID ключа в хранилище
 }
 
 type EncryptData struct {
@@ -26,11 +29,279 @@ type ResultData struct {
 }
 
 type Keys struct {
+	ID      string `json:"id,omitempty"  form:"id"` // Только в режиме KMS
 	Public  string `json:"public"  form:"public"`
-	Private string `json:"private"  form:"private"`
+	Private string `json:"private,omitempty"  form:"private"`
 }
 
 type PublicKey struct {
-	X int64 `json:"x" form:"x"`
-	Y int64 `json:"y" form:"y"`
+	X        int64 `json:"x" form:"x"`
+	Y        int64 `json:"y" form:"y"`
+	Infinity bool  `json:"infinity,omitempty" form:"infinity"` // Точка на бесконечности
+}
+
+type ReencryptionKeyRequest struct {
+	PEMKey    string `json:"pemKey" binding:"required"`    // Приватный ключ делегирующего
+	PublicKey string `json:"publicKey" binding:"required"` // Публичный ключ получателя
+}
+
+type ReencryptionKey struct {
+	ReKey string `json:"reKey"`
+}
+
+type ReencryptRequest struct {
+	Text  string `json:"text" binding:"required"`
+	ReKey string `json:"reKey" binding:"required"`
+}
+
+type SplitKeyRequest struct {
+	PEMKey    string `json:"pemKey" binding:"required"`
+	Threshold int    `json:"threshold" binding:"required,min=1"`
+	Shares    int    `json:"shares" binding:"required,gtefield=Threshold"`
+}
+
+type KeyShares struct {
+	Shares []string `json:"shares"`
+}
+
+type PartialDecryptRequest struct {
+	Text  string `json:"text" binding:"required"`
+	Share string `json:"share" binding:"required"`
+}
+
+type DecryptionShare struct {
+	Partial string `json:"partial"`
+}
+
+type CombineSharesRequest struct {
+	Text      string   `json:"text" binding:"required"`
+	PublicKey string   `json:"publicKey" binding:"required"`
+	Partials  []string `json:"partials" binding:"required,min=1"`
+}
+
+type CurvePoints struct {
+	Curve  string      `json:"curve"`
+	Order  int64       `json:"order"`
+	Points []PublicKey `json:"points"`
+}
+
+type PointRequest struct {
+	Curve EllipticArgs `json:"curve"`
+	P     PublicKey    `json:"p"`
+}
+
+type AddPointsRequest struct {
+	Curve EllipticArgs `json:"curve"`
+	P     PublicKey    `json:"p"`
+	Q     PublicKey    `json:"q"`
+}
+
+type MultiplyRequest struct {
+	Curve EllipticArgs `json:"curve"`
+	P     PublicKey    `json:"p"`
+	K     int64        `json:"k"`
+}
+
+type PointResult struct {
+	Result PublicKey `json:"result"`
+}
+
+type LadderStep struct {
+	Bit       int       `json:"bit"`
+	Operation string    `json:"operation"`
+	Scalar    int64     `json:"scalar"`
+	Point     PublicKey `json:"point"`
+}
+
+type MultiplyResult struct {
+	Result PublicKey    `json:"result"`
+	Steps  []LadderStep `json:"steps"`
+}
+
+type OrderResult struct {
+	Order int64 `json:"order"`
+}
+
+type DiscreteLogRequest struct {
+	Curve  EllipticArgs `json:"curve"`
+	G      PublicKey    `json:"g"`
+	Q      PublicKey    `json:"q"`
+	Method string       `json:"method"` // bsgs, rho или pohlig-hellman, пустое значение - все методы
+}
+
+type DiscreteLogSolution struct {
+	Method      string `json:"method"`
+	K           int64  `json:"k"`
+	Iterations  int64  `json:"iterations"`
+	Duration    string `json:"duration"`
+	Nanoseconds int64  `json:"nanoseconds"`
+}
+
+type DiscreteLogResult struct {
+	Order     int64                 `json:"order"`
+	Solutions []DiscreteLogSolution `json:"solutions"`
+}
+
+type TraceStep struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`  // hex
+	Secret bool   `json:"secret"` // Ключевой материал или открытый текст
+}
+
+type ExplainedResult struct {
+	Result string      `json:"result"`
+	Steps  []TraceStep `json:"steps"`
+}
+
+type AgreeRequest struct {
+	PEMKey    string `json:"pemKey"`                          // Свой приватный ключ
+	KeyID     string `json:"keyId"`                           // Или ID ключа в хранилище
+	PublicKey string `json:"publicKey" binding:"required"`    // Публичный ключ собеседника
+	Length    int    `json:"length" binding:"required,min=1"` // Длина ключа в байтах
+	Salt      string `json:"salt"`                            // base64, необязательно
+	Info      string `json:"info"`                            // Необязательно
+}
+
+type SharedKey struct {
+	Key string `json:"key"` // base64
+}
+
+type CreateKeyRequest struct {
+	Curve string `json:"curve"` // По умолчанию P-256
+	Suite string `json:"suite"` // По умолчанию набор кривой
+}
+
+type StoredKey struct {
+	ID        string    `json:"id"`
+	Curve     string    `json:"curve"`
+	Suite     string    `json:"suite"`
+	CreatedAt time.Time `json:"createdAt"`
+	Public    string    `json:"public"`
+}
+
+type DataKeyRequest struct {
+	PEMKey string `json:"pemKey"` // Публичный ключ
+	KeyID  string `json:"keyId"`  // Или ID ключа в хранилище
+	Size   int    `json:"size"`   // 16, 24 или 32 байта, по умолчанию 32
+}
+
+type DataKey struct {
+	Plaintext string `json:"plaintext,omitempty"` // base64
+	Wrapped   string `json:"wrapped,omitempty"`
+}
+
+type UnwrapDataKeyRequest struct {
+	Wrapped string `json:"wrapped" binding:"required"`
+	PEMKey  string `json:"pemKey"` // Приватный ключ
+	KeyID   string `json:"keyId"`  // Или ID ключа в хранилище
+}
+
+type Capabilities struct {
+	Curves    []CurveCapability    `json:"curves"`
+	Suites    []SuiteCapability    `json:"suites"`
+	Schemes   []SchemeCapability   `json:"schemes"`
+	KDFs      []KDFCapability      `json:"kdfs"`
+	Encodings []EncodingCapability `json:"encodings"`
+}
+
+type CurveCapability struct {
+	Name         string   `json:"name"`
+	OID          string   `json:"oid"`
+	Bits         int      `json:"bits"`
+	SecurityBits int      `json:"securityBits"`
+	DefaultSuite string   `json:"defaultSuite,omitempty"`
+	Suites       []string `json:"suites"` // Наборы, совместимые с кривой
+}
+
+type SuiteCapability struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Cipher       string `json:"cipher"`
+	MAC          string `json:"mac"`
+	KDF          string `json:"kdf"`
+	KeyBits      int    `json:"keyBits"`
+	MacBits      int    `json:"macBits"`
+	SecurityBits int    `json:"securityBits"`
+}
+
+type SchemeCapability struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Caveats     string `json:"caveats,omitempty"` // Ограничения безопасности
+}
+
+type KDFCapability struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Hashes      []string `json:"hashes"`
+}
+
+type EncodingCapability struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ErrorResponse struct {
+	Code  string `json:"code"`            // Стабильный код ошибки
+	Error string `json:"error"`           // Сообщение для человека
+	Scope string `json:"scope,omitempty"` // Недостающее право доступа
+}
+
+type EncryptRequestV2 struct {
+	Plaintext          string `json:"plaintext" binding:"required"`
+	PlaintextEncoding  string `json:"plaintextEncoding"`  // utf8 (по умолчанию), base64 или hex
+	CiphertextEncoding string `json:"ciphertextEncoding"` // base64 (по умолчанию) или hex
+	PublicKey          string `json:"publicKey"`          // Публичный ключ в PEM
+	KeyID              string `json:"keyId"`              // Или ID ключа в хранилище
+}
+
+type EncryptResponseV2 struct {
+	Ciphertext string `json:"ciphertext"`
+	Encoding   string `json:"encoding"`
+	KeyID      string `json:"keyId,omitempty"`
+	Curve      string `json:"curve"`
+	Suite      string `json:"suite"`
+}
+
+type DecryptRequestV2 struct {
+	Ciphertext         string `json:"ciphertext" binding:"required"`
+	CiphertextEncoding string `json:"ciphertextEncoding"` // base64 (по умолчанию) или hex
+	PlaintextEncoding  string `json:"plaintextEncoding"`  // utf8 (по умолчанию), base64 или hex
+	PrivateKey         string `json:"privateKey"`         // Приватный ключ в PEM, не в режиме KMS
+	KeyID              string `json:"keyId"`              // Или ID ключа в хранилище
+}
+
+type DecryptResponseV2 struct {
+	Plaintext string `json:"plaintext"`
+	Encoding  string `json:"encoding"`
+	KeyID     string `json:"keyId,omitempty"`
+	Curve     string `json:"curve"`
+	Suite     string `json:"suite"`
+}
+
+type GenerateKeyRequestV2 struct {
+	Curve string `json:"curve"` // По умолчанию P-256
+	Suite string `json:"suite"` // По умолчанию набор кривой
+}
+
+type KeyPairV2 struct {
+	KeyID      string `json:"keyId,omitempty"` // Только в режиме KMS
+	Curve      string `json:"curve"`
+	Suite      string `json:"suite"`
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey,omitempty"` // Не в режиме KMS
+}
+
+type InspectRequestV2 struct {
+	PublicKey string `json:"publicKey"` // Публичный ключ в PEM
+	KeyID     string `json:"keyId"`     // Или ID ключа в хранилище
+}
+
+type KeyInfoV2 struct {
+	KeyID        string `json:"keyId,omitempty"`
+	Curve        string `json:"curve"`
+	Suite        string `json:"suite"`
+	SecurityBits int    `json:"securityBits"`
+	Fingerprint  string `json:"fingerprint"`
+	PublicKey    string `json:"publicKey"`
 }
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/axidex/elliptic/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// operation tracks one cryptographic operation of a request. When it is done
+// it is counted in the metrics and written to the audit log.
+type operation struct {
+	app     *App
+	metrics *metrics.Operation
+	event   audit.Event
+}
+
+// operation starts an operation of the given audit action. name labels it in
+// metrics and the audit log, size is the size of its input.
+func (app *App) operation(c *gin.Context, action, name string, size int) *operation {
+	return &operation{
+		app:     app,
+		metrics: metrics.Start(name),
+		event: audit.Event{
+			Action:    action,
+			Operation: name,
+			Caller:    clientID(c),
+			Size:      size,
+		},
+	}
+}
+
+// Key sets the key used by the operation.
+func (op *operation) Key(pub *cypher.PublicKey) {
+	op.metrics.Key(pub)
+	op.event.Fingerprint = audit.Fingerprint(pub)
+	op.event.Curve, op.event.Suite = op.metrics.Labels()
+}
+
+// Detail adds a value to the audit event. Secrets are redacted by type.
+func (op *operation) Detail(key string, value any) {
+	if op.event.Details == nil {
+		op.event.Details = make(map[string]any)
+	}
+	op.event.Details[key] = value
+}
+
+// Done finishes the operation with the outcome of err.
+func (op *operation) Done(err error) {
+	op.metrics.Done(err)
+	op.record(metrics.Outcome(err))
+}
+
+// InvalidKey finishes an operation whose key could not be parsed or found.
+func (op *operation) InvalidKey() {
+	op.metrics.InvalidKey()
+	op.record(metrics.OutcomeInvalidKey)
+}
+
+func (op *operation) record(outcome string) {
+	op.event.Outcome = outcome
+	if err := op.app.audit.Record(op.event); err != nil {
+		op.app.logger.Errorf("Audit log err: %s", err)
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Generate a re-encryption key
+// @Description Delegate decryption from the owner of the private key to the owner of the public key. The scheme is not collusion-safe: the re-encryption key together with the delegatee private key reveals the delegator private key, so give it only to a proxy that never colludes with the delegatee. Not available in KMS mode
+// @Tags reencryption
+// @Accept application/json
+// @Produce json
+// @Param payload body ReencryptionKeyRequest true "Payload"
+// @Success 200 {object} ReencryptionKey
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/rekey [post]
+func (app *App) generateReencryptionKey(c *gin.Context) {
+	var req ReencryptionKeyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	app.logger.Infof("Got task re-encryption key generation")
+
+	op := app.operation(c, audit.ActionGenerateKey, "generate_reencryption_key", 0)
+
+	// The caller picks the delegatee, and the re-encryption key together with
+	// the delegatee key reveals the delegator key, so stored keys can't be
+	// delegated either.
+	if app.config.KMS.Enabled {
+		op.InvalidKey()
+		app.logger.Warnf("Re-encryption key generation in KMS mode")
+		fail(c, http.StatusForbidden, CodeForbidden, "re-encryption keys are not available in KMS mode")
+		return
+	}
+
+	delegator, err := cypher.ImportPrivatePEM([]byte(req.PEMKey))
+	if err != nil {
+		op.InvalidKey()
+		app.logger.Infof("Not valid key: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+		return
+	}
+	op.Key(&delegator.PublicKey)
+
+	delegatee, err := cypher.ImportPublicPEM([]byte(req.PublicKey))
+	if err != nil {
+		op.InvalidKey()
+		app.logger.Infof("Not valid key: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+		return
+	}
+	op.Detail("delegatee", audit.Fingerprint(delegatee))
+
+	rk, err := delegator.GenerateReencryptionKey(rand.Reader, delegatee)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Re-encryption key error %v", err)
+		failCypher(c, err, "re-encryption key error")
+		return
+	}
+
+	reKey, err := cypher.ExportReencryptionKeyPEM(rk)
+	if err != nil {
+		app.logger.Errorf("Encoding err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ReencryptionKey{
+		ReKey: string(reKey),
+	})
+}
+
+// @Summary Re-encrypt data
+// @Description Transform a ciphertext for the delegator into one for the delegatee without decrypting it. The proxy learns nothing on its own, but must not collude with the delegatee, see /rekey
+// @Tags reencryption
+// @Accept application/json
+// @Produce text/plain
+// @Param payload body ReencryptRequest true "Payload"
+// @Success 200 {string} string "Re-encrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Router /api/cypher/elliptic/reencrypt [post]
+func (app *App) reencrypt(c *gin.Context) {
+	var req ReencryptRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	app.logger.Infof("Got task re-encryption")
+
+	op := app.operation(c, audit.ActionEncrypt, "reencrypt", len(req.Text))
+	rk, err := cypher.ImportReencryptionKeyPEM([]byte(req.ReKey))
+	if err != nil {
+		op.InvalidKey()
+		app.logger.Infof("Not valid key: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+		return
+	}
+	op.Key(rk.Delegatee)
+
+	reencryptedText, err := cypher.Reencrypt(rk, req.Text)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Re-encryption error %v", err)
+		if errors.Is(err, cypher.ErrInvalidMessage) {
+			fail(c, http.StatusBadRequest, CodeInvalidRequest, "invalid ciphertext")
+			return
+		}
+		failCypher(c, err, "re-encryption error")
+		return
+	}
+
+	c.String(http.StatusOK, reencryptedText)
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"crypto/rand"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+)
+
+type encryptScheme func(rand io.Reader, pub *cypher.PublicKey, m []byte) (string, error)
+
+type decryptScheme func(prv *cypher.PrivateKey, ct string) ([]byte, error)
+
+// encryptWith handles an encryption request for one of the alternative
+// schemes that reuse the ECIES key types. operation labels its metrics.
+func (app *App) encryptWith(c *gin.Context, name, operation string, encrypt encryptScheme) {
+	var req EncryptRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	if !app.checkPlaintext(c, len(req.Text)) {
+		return
+	}
+
+	app.logger.Infof("Got task %s encryption", name)
+	op := app.operation(c, audit.ActionEncrypt, operation, len(req.Text))
+	key, ok := app.requestPublicKey(c, req.PEMKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(key)
+
+	encryptedText, err := encrypt(rand.Reader, key, []byte(req.Text))
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Encryption error %v", err)
+		failCypher(c, err, "encryption error")
+		return
+	}
+
+	c.String(http.StatusOK, encryptedText)
+}
+
+// decryptWith handles a decryption request for one of the alternative
+// schemes that reuse the ECIES key types. operation labels its metrics.
+func (app *App) decryptWith(c *gin.Context, name, operation string, decrypt decryptScheme) {
+	var req EncryptRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	app.logger.Infof("Got task %s decryption", name)
+	op := app.operation(c, audit.ActionDecrypt, operation, len(req.Text))
+	key, ok := app.requestPrivateKey(c, req.PEMKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(&key.PublicKey)
+
+	decryptText, err := decrypt(key, req.Text)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Decryption error %v", err)
+		failDecryption(c)
+		return
+	}
+
+	c.String(http.StatusOK, string(decryptText))
+}
+
+// @Summary Encrypt data with EC-ElGamal
+// @Description Encrypt the provided text using EC-ElGamal with Koblitz encoding and the given public key or stored key
+// @Tags schemes
+// @Accept application/json
+// @Produce text/plain
+// @Param payload body EncryptRequest true "Payload"
+// @Success 200 {string} string "Encrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elgamal/encrypt [post]
+func (app *App) encryptElGamal(c *gin.Context) {
+	app.encryptWith(c, "EC-ElGamal", "elgamal_encrypt", cypher.EncryptElGamal)
+}
+
+// @Summary Decrypt data with EC-ElGamal
+// @Description Decrypt the provided EC-ElGamal ciphertext using the given private key or, in KMS mode, only the stored key
+// @Tags schemes
+// @Accept application/json
+// @Produce text/plain
+// @Param payload body EncryptRequest true "Payload"
+// @Success 200 {string} string "Decrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elgamal/decrypt [post]
+func (app *App) decryptElGamal(c *gin.Context) {
+	app.decryptWith(c, "EC-ElGamal", "elgamal_decrypt", (*cypher.PrivateKey).DecryptElGamal)
+}
+
+// @Summary Encrypt data with Menezes–Vanstone
+// @Description Encrypt the provided text using the Menezes–Vanstone cryptosystem and the given public key or stored key
+// @Tags schemes
+// @Accept application/json
+// @Produce text/plain
+// @Param payload body EncryptRequest true "Payload"
+// @Success 200 {string} string "Encrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/menezes-vanstone/encrypt [post]
+func (app *App) encryptMenezesVanstone(c *gin.Context) {
+	app.encryptWith(c, "Menezes–Vanstone", "menezes_vanstone_encrypt", cypher.EncryptMenezesVanstone)
+}
+
+// @Summary Decrypt data with Menezes–Vanstone
+// @Description Decrypt the provided Menezes–Vanstone ciphertext using the given private key or, in KMS mode, only the stored key
+// @Tags schemes
+// @Accept application/json
+// @Produce text/plain
+// @Param payload body EncryptRequest true "Payload"
+// @Success 200 {string} string "Decrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/menezes-vanstone/decrypt [post]
+func (app *App) decryptMenezesVanstone(c *gin.Context) {
+	app.decryptWith(c, "Menezes–Vanstone", "menezes_vanstone_decrypt", (*cypher.PrivateKey).DecryptMenezesVanstone)
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Split a private key
+// @Description Split the private key into Shamir shares, any threshold of which can decrypt together. Not available in KMS mode
+// @Tags threshold
+// @Accept application/json
+// @Produce json
+// @Param payload body SplitKeyRequest true "Payload"
+// @Success 200 {object} KeyShares
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/threshold/split [post]
+func (app *App) splitKey(c *gin.Context) {
+	var req SplitKeyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	app.logger.Infof("Got task key splitting %d of %d", req.Threshold, req.Shares)
+
+	op := app.operation(c, audit.ActionGenerateKey, "threshold_split", 0)
+	op.Detail("threshold", req.Threshold)
+	op.Detail("shares", req.Shares)
+
+	// Enough shares rebuild the key, so stored keys can't be split either.
+	if app.config.KMS.Enabled {
+		op.InvalidKey()
+		app.logger.Warnf("Key splitting in KMS mode")
+		fail(c, http.StatusForbidden, CodeForbidden, "key splitting is not available in KMS mode")
+		return
+	}
+
+	key, err := cypher.ImportPrivatePEM([]byte(req.PEMKey))
+	if err != nil {
+		op.InvalidKey()
+		app.logger.Infof("Not valid key: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+		return
+	}
+	op.Key(&key.PublicKey)
+
+	shares, err := cypher.SplitKey(rand.Reader, key, req.Threshold, req.Shares)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Splitting error %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidThreshold, "invalid threshold")
+		return
+	}
+
+	resp := KeyShares{Shares: make([]string, 0, len(shares))}
+	for _, share := range shares {
+		encoded, err := cypher.ExportKeySharePEM(share)
+		if err != nil {
+			app.logger.Errorf("Encoding err: %s", err)
+			fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+			return
+		}
+		resp.Shares = append(resp.Shares, string(encoded))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Partially decrypt data
+// @Description Compute the decryption share of one key share for the given ciphertext
+// @Tags threshold
+// @Accept application/json
+// @Produce json
+// @Param payload body PartialDecryptRequest true "Payload"
+// @Success 200 {object} DecryptionShare
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/threshold/partial [post]
+func (app *App) partialDecrypt(c *gin.Context) {
+	var req PartialDecryptRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	app.logger.Infof("Got task partial decryption")
+
+	op := app.operation(c, audit.ActionDecrypt, "threshold_partial", len(req.Text))
+	share, err := cypher.ImportKeySharePEM([]byte(req.Share))
+	if err != nil {
+		op.InvalidKey()
+		app.logger.Infof("Not valid share: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidShare, "provide valid share")
+		return
+	}
+	op.Key(&share.PublicKey)
+	op.Detail("share_index", share.Index)
+
+	part, err := share.PartialDecrypt(req.Text)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Partial decryption error %v", err)
+		failDecryption(c)
+		return
+	}
+
+	encoded, err := cypher.ExportDecryptionSharePEM(part)
+	if err != nil {
+		app.logger.Errorf("Encoding err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding share error")
+		return
+	}
+
+	c.JSON(http.StatusOK, DecryptionShare{Partial: string(encoded)})
+}
+
+// @Summary Combine decryption shares
+// @Description Combine at least threshold decryption shares and decrypt the ciphertext
+// @Tags threshold
+// @Accept application/json
+// @Produce text/plain
+// @Param payload body CombineSharesRequest true "Payload"
+// @Success 200 {string} string "Decrypted data"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/cypher/elliptic/threshold/combine [post]
+func (app *App) combineShares(c *gin.Context) {
+	var req CombineSharesRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	app.logger.Infof("Got task combining %d decryption shares", len(req.Partials))
+
+	op := app.operation(c, audit.ActionDecrypt, "threshold_combine", len(req.Text))
+	key, err := cypher.ImportPublicPEM([]byte(req.PublicKey))
+	if err != nil {
+		op.InvalidKey()
+		app.logger.Infof("Not valid key: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidKey, "provide valid key")
+		return
+	}
+	op.Key(key)
+
+	parts := make([]*cypher.DecryptionShare, 0, len(req.Partials))
+	for _, partial := range req.Partials {
+		part, err := cypher.ImportDecryptionSharePEM([]byte(partial))
+		if err != nil {
+			app.logger.Infof("Not valid share: %v", err)
+			fail(c, http.StatusBadRequest, CodeInvalidShare, "provide valid share")
+			return
+		}
+		parts = append(parts, part)
+	}
+
+	decryptText, err := cypher.CombineShares(rand.Reader, key, req.Text, parts, nil, nil)
+	op.Done(err)
+	switch {
+	case errors.Is(err, cypher.ErrNotEnoughShares),
+		errors.Is(err, cypher.ErrDuplicateShare),
+		errors.Is(err, cypher.ErrInvalidShareCurve),
+		errors.Is(err, cypher.ErrInvalidDecryptPart):
+		// Problems with the shares tell nothing about the plaintext.
+		failCypher(c, err, "decryption error")
+		return
+	case err != nil:
+		app.logger.Infof("Decryption error %v", err)
+		failDecryption(c)
+		return
+	}
+
+	c.String(http.StatusOK, string(decryptText))
+}
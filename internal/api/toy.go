@@ -0,0 +1,306 @@
+package api
+
+import (
+	"errors"
+	"github.com/axidex/elliptic/internal/toycurve"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func toyCurve(args EllipticArgs) (*toycurve.Curve, error) {
+	return toycurve.New(int64(args.A), int64(args.B), int64(args.P))
+}
+
+func toyPoint(curve *toycurve.Curve, p PublicKey) (toycurve.Point, error) {
+	point := toycurve.Point{X: p.X, Y: p.Y, Infinity: p.Infinity}
+	if point.Infinity {
+		return toycurve.Infinity, nil
+	}
+	if !curve.IsOnCurve(point) {
+		return point, toycurve.ErrNotOnCurve
+	}
+	return point, nil
+}
+
+func fromToyPoint(p toycurve.Point) PublicKey {
+	return PublicKey{X: p.X, Y: p.Y, Infinity: p.Infinity}
+}
+
+// @Summary List curve points
+// @Description List every point of a small curve y^2 = x^3 + ax + b over F_p
+// @Tags toy
+// @Produce json
+// @Param a query int false "Coefficient a"
+// @Param b query int false "Coefficient b"
+// @Param p query int true "Prime modulus"
+// @Success 200 {object} CurvePoints
+// @Failure 400 {object} ErrorResponse
+// @Router /api/toy/points [get]
+func (app *App) toyPoints(c *gin.Context) {
+	var args EllipticArgs
+
+	if err := c.ShouldBindQuery(&args); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	curve, err := toyCurve(args)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	points := curve.Points()
+	resp := CurvePoints{
+		Curve:  curve.String(),
+		Order:  int64(len(points)),
+		Points: make([]PublicKey, 0, len(points)),
+	}
+	for _, p := range points {
+		resp.Points = append(resp.Points, fromToyPoint(p))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Group order
+// @Description Count the points of a small curve, including the point at infinity
+// @Tags toy
+// @Produce json
+// @Param a query int false "Coefficient a"
+// @Param b query int false "Coefficient b"
+// @Param p query int true "Prime modulus"
+// @Success 200 {object} OrderResult
+// @Failure 400 {object} ErrorResponse
+// @Router /api/toy/group-order [get]
+func (app *App) toyGroupOrder(c *gin.Context) {
+	var args EllipticArgs
+
+	if err := c.ShouldBindQuery(&args); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	curve, err := toyCurve(args)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, OrderResult{Order: curve.GroupOrder()})
+}
+
+// @Summary Add two points
+// @Description Compute p + q on a small curve
+// @Tags toy
+// @Accept application/json
+// @Produce json
+// @Param payload body AddPointsRequest true "Payload"
+// @Success 200 {object} PointResult
+// @Failure 400 {object} ErrorResponse
+// @Router /api/toy/add [post]
+func (app *App) toyAdd(c *gin.Context) {
+	var req AddPointsRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	curve, err := toyCurve(req.Curve)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	p, err := toyPoint(curve, req.P)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	q, err := toyPoint(curve, req.Q)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, PointResult{Result: fromToyPoint(curve.Add(p, q))})
+}
+
+// @Summary Double a point
+// @Description Compute 2p on a small curve
+// @Tags toy
+// @Accept application/json
+// @Produce json
+// @Param payload body PointRequest true "Payload"
+// @Success 200 {object} PointResult
+// @Failure 400 {object} ErrorResponse
+// @Router /api/toy/double [post]
+func (app *App) toyDouble(c *gin.Context) {
+	var req PointRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	curve, err := toyCurve(req.Curve)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	p, err := toyPoint(curve, req.P)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, PointResult{Result: fromToyPoint(curve.Double(p))})
+}
+
+// @Summary Multiply a point by a scalar
+// @Description Compute k·p on a small curve and return every step of the double-and-add ladder
+// @Tags toy
+// @Accept application/json
+// @Produce json
+// @Param payload body MultiplyRequest true "Payload"
+// @Success 200 {object} MultiplyResult
+// @Failure 400 {object} ErrorResponse
+// @Router /api/toy/multiply [post]
+func (app *App) toyMultiply(c *gin.Context) {
+	var req MultiplyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	curve, err := toyCurve(req.Curve)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	p, err := toyPoint(curve, req.P)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	result, steps := curve.ScalarMult(p, req.K)
+	resp := MultiplyResult{
+		Result: fromToyPoint(result),
+		Steps:  make([]LadderStep, 0, len(steps)),
+	}
+	for _, step := range steps {
+		resp.Steps = append(resp.Steps, LadderStep{
+			Bit:       step.Bit,
+			Operation: step.Operation,
+			Scalar:    step.Scalar,
+			Point:     fromToyPoint(step.Point),
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Point order
+// @Description Compute the order of a point on a small curve
+// @Tags toy
+// @Accept application/json
+// @Produce json
+// @Param payload body PointRequest true "Payload"
+// @Success 200 {object} OrderResult
+// @Failure 400 {object} ErrorResponse
+// @Router /api/toy/order [post]
+func (app *App) toyOrder(c *gin.Context) {
+	var req PointRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	curve, err := toyCurve(req.Curve)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	p, err := toyPoint(curve, req.P)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, OrderResult{Order: curve.Order(p)})
+}
+
+// @Summary Solve a discrete logarithm
+// @Description Recover k with q = k·g on a small curve using baby-step giant-step, Pollard's rho or Pohlig–Hellman
+// @Tags toy
+// @Accept application/json
+// @Produce json
+// @Param payload body DiscreteLogRequest true "Payload"
+// @Success 200 {object} DiscreteLogResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api/toy/dlog [post]
+func (app *App) toyDiscreteLog(c *gin.Context) {
+	var req DiscreteLogRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	curve, err := toyCurve(req.Curve)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	g, err := toyPoint(curve, req.G)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	q, err := toyPoint(curve, req.Q)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	methods := toycurve.Methods
+	if req.Method != "" {
+		methods = []string{req.Method}
+	}
+
+	var resp DiscreteLogResult
+	for _, method := range methods {
+		solution, err := curve.SolveDiscreteLog(method, g, q)
+		switch {
+		case errors.Is(err, toycurve.ErrNoSolution):
+			fail(c, http.StatusUnprocessableEntity, CodeNoSolution, err.Error())
+			return
+		case err != nil:
+			fail(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+			return
+		}
+
+		app.logger.Infof("Solved discrete log with %s in %d iterations", method, solution.Iterations)
+		resp.Order = solution.Order
+		resp.Solutions = append(resp.Solutions, DiscreteLogSolution{
+			Method:      solution.Method,
+			K:           solution.K,
+			Iterations:  solution.Iterations,
+			Duration:    solution.Duration.String(),
+			Nanoseconds: solution.Duration.Nanoseconds(),
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
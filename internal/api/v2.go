@@ -0,0 +1,336 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/axidex/elliptic/internal/audit"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/axidex/elliptic/internal/keystore"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"unicode/utf8"
+)
+
+// Encodings of binary values in v2 requests and responses.
+const (
+	encodingUTF8   = "utf8"
+	encodingBase64 = "base64"
+	encodingHex    = "hex"
+)
+
+var errInvalidUTF8 = errors.New("not valid UTF-8")
+
+// resolveEncoding checks an encoding name of a request against the allowed
+// ones. An empty name selects the first.
+func resolveEncoding(name string, allowed ...string) (string, bool) {
+	if name == "" {
+		return allowed[0], true
+	}
+	for _, encoding := range allowed {
+		if name == encoding {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func decodeData(data, encoding string) ([]byte, error) {
+	switch encoding {
+	case encodingBase64:
+		return base64.StdEncoding.DecodeString(data)
+	case encodingHex:
+		return hex.DecodeString(data)
+	default:
+		return []byte(data), nil
+	}
+}
+
+func encodeData(data []byte, encoding string) (string, error) {
+	switch encoding {
+	case encodingBase64:
+		return base64.StdEncoding.EncodeToString(data), nil
+	case encodingHex:
+		return hex.EncodeToString(data), nil
+	default:
+		if !utf8.Valid(data) {
+			return "", errInvalidUTF8
+		}
+		return string(data), nil
+	}
+}
+
+// describeKey looks up the curve and suite of a key. Keys without explicit
+// parameters use the default suite of their curve.
+func describeKey(pub *cypher.PublicKey) (cypher.Curve, cypher.Suite) {
+	curve, _ := cypher.LookupCurve(pub.Curve)
+	suite, ok := cypher.LookupSuite(pub.Params)
+	if !ok && pub.Params == nil {
+		suite, _ = cypher.SuiteByName(curve.DefaultSuite)
+	}
+	return curve, suite
+}
+
+// @Summary Encrypt data
+// @Description Encrypt the plaintext with the given public key or stored key. The ciphertext is the same as of /api/cypher/elliptic/encrypt, so both versions can decrypt it
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param payload body EncryptRequestV2 true "Payload"
+// @Success 200 {object} EncryptResponseV2
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/encrypt [post]
+func (app *App) encryptV2(c *gin.Context) {
+	var req EncryptRequestV2
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	plaintextEncoding, ok := resolveEncoding(req.PlaintextEncoding, encodingUTF8, encodingBase64, encodingHex)
+	if !ok {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "unsupported plaintext encoding")
+		return
+	}
+	ciphertextEncoding, ok := resolveEncoding(req.CiphertextEncoding, encodingBase64, encodingHex)
+	if !ok {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "unsupported ciphertext encoding")
+		return
+	}
+
+	plaintext, err := decodeData(req.Plaintext, plaintextEncoding)
+	if err != nil {
+		app.logger.Warnf("Invalid plaintext: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "plaintext is not valid "+plaintextEncoding)
+		return
+	}
+	if len(plaintext) == 0 {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "plaintext is empty")
+		return
+	}
+	if !app.checkPlaintext(c, len(plaintext)) {
+		return
+	}
+
+	app.logger.Infof("Got task encryption")
+	op := app.operation(c, audit.ActionEncrypt, "encrypt", len(plaintext))
+	key, ok := app.requestPublicKey(c, req.PublicKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(key)
+
+	ct, err := cypher.EncryptRaw(c.Request.Context(), rand.Reader, key, plaintext, nil, nil)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Encryption error %v", err)
+		failCypher(c, err, "encryption error")
+		return
+	}
+
+	ciphertext, _ := encodeData(ct, ciphertextEncoding)
+	curve, suite := describeKey(key)
+	c.JSON(http.StatusOK, EncryptResponseV2{
+		Ciphertext: ciphertext,
+		Encoding:   ciphertextEncoding,
+		KeyID:      req.KeyID,
+		Curve:      curve.Name,
+		Suite:      suite.Name,
+	})
+}
+
+// @Summary Decrypt data
+// @Description Decrypt the ciphertext with the given private key or, in KMS mode, only the stored key. Plaintexts that aren't valid UTF-8 must be requested as base64 or hex
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param payload body DecryptRequestV2 true "Payload"
+// @Success 200 {object} DecryptResponseV2
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/decrypt [post]
+func (app *App) decryptV2(c *gin.Context) {
+	var req DecryptRequestV2
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	ciphertextEncoding, ok := resolveEncoding(req.CiphertextEncoding, encodingBase64, encodingHex)
+	if !ok {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "unsupported ciphertext encoding")
+		return
+	}
+	plaintextEncoding, ok := resolveEncoding(req.PlaintextEncoding, encodingUTF8, encodingBase64, encodingHex)
+	if !ok {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "unsupported plaintext encoding")
+		return
+	}
+
+	ct, err := decodeData(req.Ciphertext, ciphertextEncoding)
+	if err != nil {
+		app.logger.Warnf("Invalid ciphertext: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "ciphertext is not valid "+ciphertextEncoding)
+		return
+	}
+
+	app.logger.Infof("Got task decryption")
+	op := app.operation(c, audit.ActionDecrypt, "decrypt", len(ct))
+	key, ok := app.requestPrivateKey(c, req.PrivateKey, req.KeyID)
+	if !ok {
+		op.InvalidKey()
+		return
+	}
+	op.Key(&key.PublicKey)
+
+	m, err := key.DecryptRaw(c.Request.Context(), rand.Reader, ct, nil, nil)
+	op.Done(err)
+	if err != nil {
+		app.logger.Infof("Decryption error %v", err)
+		failDecryption(c)
+		return
+	}
+
+	plaintext, err := encodeData(m, plaintextEncoding)
+	if err != nil {
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "plaintext is not valid UTF-8, request base64 or hex")
+		return
+	}
+
+	curve, suite := describeKey(&key.PublicKey)
+	c.JSON(http.StatusOK, DecryptResponseV2{
+		Plaintext: plaintext,
+		Encoding:  plaintextEncoding,
+		KeyID:     req.KeyID,
+		Curve:     curve.Name,
+		Suite:     suite.Name,
+	})
+}
+
+// @Summary Generate a keypair
+// @Description Generate a keypair on the given curve with the given suite. In KMS mode the private key is kept on the server and its ID is returned instead. See /api/cypher/capabilities for the curves and suites
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param payload body GenerateKeyRequestV2 false "Payload"
+// @Success 200 {object} KeyPairV2
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/keys [post]
+func (app *App) generateKeyV2(c *gin.Context) {
+	var req GenerateKeyRequestV2
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			app.logger.Warnf("Invalid input: %v", err)
+			fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+			return
+		}
+	}
+
+	curve, suite, ok := keyParams(req.Curve, req.Suite)
+	if !ok {
+		app.logger.Warnf("Unsupported curve %q or suite %q", req.Curve, req.Suite)
+		fail(c, http.StatusBadRequest, CodeUnsupportedParameters, "unsupported curve or suite")
+		return
+	}
+
+	op := app.operation(c, audit.ActionGenerateKey, "generate_key", 0)
+	prv, err := cypher.GenerateKey(rand.Reader, curve.Curve, suite.Params)
+	if err != nil {
+		op.Done(err)
+		app.logger.Errorf("GenerateKey err: %s", err)
+		failCypher(c, err, "generating keys error")
+		return
+	}
+	op.Key(&prv.PublicKey)
+
+	resp := KeyPairV2{Curve: curve.Name, Suite: suite.Name}
+	if app.config.KMS.Enabled {
+		key, err := keystore.NewKey(prv)
+		if err == nil {
+			op.Detail("key_id", key.ID)
+			err = app.keys.Put(c.Request.Context(), key)
+		}
+		op.Done(err)
+		if err != nil {
+			app.logger.Errorf("Key store err: %s", err)
+			fail(c, http.StatusInternalServerError, CodeInternal, "storing key error")
+			return
+		}
+		app.logger.Infof("Stored key %s", key.ID)
+		resp.KeyID = key.ID
+	} else {
+		op.Done(nil)
+		private, err := cypher.ExportPrivatePEM(prv)
+		if err != nil {
+			app.logger.Errorf("Encoding err: %s", err)
+			fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+			return
+		}
+		resp.PrivateKey = string(private)
+	}
+
+	public, err := cypher.ExportPublicPEM(&prv.PublicKey)
+	if err != nil {
+		app.logger.Errorf("Encoding err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+		return
+	}
+	resp.PublicKey = string(public)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Inspect a key
+// @Description Describe a PEM public key or the public half of a stored key: its curve, suite, security level and fingerprint
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param payload body InspectRequestV2 true "Payload"
+// @Success 200 {object} KeyInfoV2
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/inspect [post]
+func (app *App) inspectV2(c *gin.Context) {
+	var req InspectRequestV2
+	if err := c.ShouldBindJSON(&req); err != nil {
+		app.logger.Warnf("Invalid input: %v", err)
+		fail(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid input")
+		return
+	}
+
+	key, ok := app.requestPublicKey(c, req.PublicKey, req.KeyID)
+	if !ok {
+		return
+	}
+
+	public, err := cypher.ExportPublicPEM(key)
+	if err != nil {
+		app.logger.Errorf("Encoding err: %s", err)
+		fail(c, http.StatusInternalServerError, CodeInternal, "encoding keys error")
+		return
+	}
+
+	curve, suite := describeKey(key)
+	info := KeyInfoV2{
+		KeyID:       req.KeyID,
+		Curve:       curve.Name,
+		Suite:       suite.Name,
+		Fingerprint: audit.Fingerprint(key),
+		PublicKey:   string(public),
+	}
+	if curve.Curve != nil && suite.Params != nil {
+		info.SecurityBits = min(curve.SecurityBits(), suite.SecurityBits())
+	}
+	c.JSON(http.StatusOK, info)
+}
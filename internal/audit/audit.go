@@ -0,0 +1,211 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/axidex/elliptic/internal/cypher"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTampered = errors.New("audit: log has been altered")
+)
+
+// Actions recorded in the audit log.
+const (
+	ActionGenerateKey = "generate_key"
+	ActionEncrypt     = "encrypt"
+	ActionDecrypt     = "decrypt"
+	ActionAgree       = "agree"
+)
+
+// Secret marks a string detail that must never be written, such as a PEM
+// private key. Other strings are written as they are.
+type Secret string
+
+// redacted replaces secret values in events.
+const redacted = "[REDACTED]"
+
+// Event is a single audited operation. Details may hold any extra values;
+// they are passed through Redact before they are written.
+type Event struct {
+	Time        time.Time      `json:"time"`
+	Action      string         `json:"action"`
+	Operation   string         `json:"operation"`
+	Caller      string         `json:"caller"`
+	Fingerprint string         `json:"fingerprint,omitempty"`
+	Curve       string         `json:"curve,omitempty"`
+	Suite       string         `json:"suite,omitempty"`
+	Outcome     string         `json:"outcome"`
+	Size        int            `json:"size"`
+	Details     map[string]any `json:"details,omitempty"`
+}
+
+// entry is one line of the log: the event chained to its predecessor. The
+// event is kept as raw JSON, so the hash covers exactly the bytes written.
+type entry struct {
+	Seq   uint64          `json:"seq"`
+	Event json.RawMessage `json:"event"`
+	Prev  string          `json:"prev"`
+	Hash  string          `json:"hash"`
+}
+
+// Log is an append-only audit log. Every entry carries the hash of the entry
+// before it, so removing, reordering or editing entries breaks the chain and
+// is detected by Verify. A nil *Log records nothing.
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+	seq  uint64
+	last string
+}
+
+// Open opens the log at path, creating it if needed. An existing log is
+// verified first and new entries continue its chain.
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Log{file: file}
+	if l.seq, l.last, err = verify(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Record appends the event to the log. The time is set if missing.
+func (l *Log) Record(event Event) error {
+	if l == nil {
+		return nil
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	event.Details = Redact(event.Details)
+
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := entry{Seq: l.seq + 1, Event: raw, Prev: l.last}
+	e.Hash = e.hash()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err = l.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err = l.file.Sync(); err != nil {
+		return err
+	}
+	l.seq, l.last = e.Seq, e.Hash
+	return nil
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// Verify checks the hash chain of a log and returns ErrTampered if any entry
+// was changed, removed or reordered. Truncating the newest entries can only
+// be detected by comparing the last hash with a copy kept elsewhere.
+func Verify(r io.Reader) error {
+	_, _, err := verify(r)
+	return err
+}
+
+func verify(r io.Reader) (seq uint64, last string, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e entry
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return 0, "", fmt.Errorf("%w: entry %d: %v", ErrTampered, seq+1, err)
+		}
+		if e.Seq != seq+1 || e.Prev != last || e.Hash != e.hash() {
+			return 0, "", fmt.Errorf("%w: entry %d", ErrTampered, seq+1)
+		}
+		seq, last = e.Seq, e.Hash
+	}
+	return seq, last, scanner.Err()
+}
+
+// hash computes SHA-256 over the sequence number, the previous hash and the
+// event.
+func (e entry) hash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%s\n", e.Seq, e.Prev)
+	h.Write(e.Event)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Fingerprint identifies a public key without revealing it: the SHA-256 of
+// its uncompressed point.
+func Fingerprint(pub *cypher.PublicKey) string {
+	if pub == nil || pub.X == nil {
+		return ""
+	}
+	sum := sha256.Sum256(elliptic.Marshal(pub.Curve, pub.X, pub.Y))
+	return "SHA256:" + hex.EncodeToString(sum[:])
+}
+
+// Redact returns a copy of details with secret values replaced. Private keys
+// and scalars are dropped, public keys are replaced by their fingerprint and
+// raw bytes, which may be plaintexts, ciphertexts or key material, by their
+// length.
+func Redact(details map[string]any) map[string]any {
+	if details == nil {
+		return nil
+	}
+	out := make(map[string]any, len(details))
+	for k, v := range details {
+		out[k] = redactValue(v)
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case Secret, *cypher.PrivateKey, cypher.PrivateKey, *cypher.HybridPrivateKey,
+		*ecdsa.PrivateKey, *big.Int, *cypher.KeyShare:
+		return redacted
+	case *cypher.PublicKey:
+		return Fingerprint(v)
+	case []byte:
+		return fmt.Sprintf("%s %d bytes", redacted, len(v))
+	case map[string]any:
+		return Redact(v)
+	case string, bool, int, int64, uint64, float64, time.Time, time.Duration, nil:
+		return v
+	default:
+		// Unknown types may hold anything, keep only the type.
+		return fmt.Sprintf("%s %T", redacted, v)
+	}
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/axidex/elliptic/config"
+)
+
+// APIKeyHeader carries a static API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeys authenticates requests by a static key from the config.
+type APIKeys struct {
+	keys []config.APIKey
+}
+
+func NewAPIKeys(keys []config.APIKey) *APIKeys {
+	return &APIKeys{keys: keys}
+}
+
+func (a *APIKeys) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k.Key)) == 1 {
+			return &Principal{Name: k.Name, Scopes: k.Scopes}, nil
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
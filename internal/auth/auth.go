@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/axidex/elliptic/config"
+)
+
+// Scopes checked by the API. A principal with ScopeAll passes every check.
+const (
+	ScopeAll          = "*"
+	ScopeEncrypt      = "encrypt"
+	ScopeDecrypt      = "decrypt"
+	ScopeKeysGenerate = "keys:generate"
+	ScopeKeysRead     = "keys:read"
+	ScopeKeysDelete   = "keys:delete"
+	ScopeToy          = "toy"
+)
+
+var (
+	// ErrNoCredentials means the request carries no credentials for an
+	// authenticator, so the next one should be tried.
+	ErrNoCredentials = errors.New("auth: no credentials")
+	// ErrInvalidCredentials means the request carries credentials that
+	// failed verification.
+	ErrInvalidCredentials = errors.New("auth: invalid credentials")
+)
+
+// Principal is an authenticated client.
+type Principal struct {
+	Name   string
+	Scopes []string
+}
+
+// HasScope reports whether the principal was granted the scope.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAll)
+}
+
+// Authenticator identifies the client of a request.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Chain tries each authenticator in turn until one finds credentials.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(r *http.Request) (*Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return p, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// New builds the authenticators enabled in the config: static API keys,
+// HMAC-signed requests and JWT bearer tokens, in that order.
+func New(cfg config.Auth) (Chain, error) {
+	var chain Chain
+	if len(cfg.APIKeys) > 0 {
+		chain = append(chain, NewAPIKeys(cfg.APIKeys))
+	}
+	if len(cfg.HMAC) > 0 {
+		chain = append(chain, NewHMAC(cfg.HMAC))
+	}
+	if len(cfg.JWT.PublicKeys) > 0 {
+		jwt, err := NewJWT(cfg.JWT)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, jwt)
+	}
+	return chain, nil
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/axidex/elliptic/config"
+)
+
+// Headers of an HMAC-signed request.
+const (
+	ClientIDHeader  = "X-Client-ID"
+	TimestampHeader = "X-Timestamp"
+	SignatureHeader = "X-Signature"
+)
+
+// MaxClockSkew is how far the timestamp of a signed request may be from the
+// server time.
+const MaxClockSkew = 5 * time.Minute
+
+// HMAC authenticates requests signed with a shared secret. The signature is
+// the hex HMAC-SHA-256 of StringToSign.
+type HMAC struct {
+	clients map[string]config.HMACClient
+	now     func() time.Time
+}
+
+func NewHMAC(clients []config.HMACClient) *HMAC {
+	h := &HMAC{clients: make(map[string]config.HMACClient, len(clients)), now: time.Now}
+	for _, c := range clients {
+		h.clients[c.ID] = c
+	}
+	return h
+}
+
+// StringToSign is the message signed by a client:
+//
+//	method \n request URI \n unix timestamp \n hex SHA-256 of the body
+func StringToSign(method, uri, timestamp string, body []byte) []byte {
+	sum := sha256.Sum256(body)
+	return []byte(method + "\n" + uri + "\n" + timestamp + "\n" + hex.EncodeToString(sum[:]))
+}
+
+// Sign computes the signature header value for a request.
+func Sign(secret string, method, uri, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(StringToSign(method, uri, timestamp, body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (h *HMAC) Authenticate(r *http.Request) (*Principal, error) {
+	id := r.Header.Get(ClientIDHeader)
+	signature := r.Header.Get(SignatureHeader)
+	if id == "" || signature == "" {
+		return nil, ErrNoCredentials
+	}
+	client, ok := h.clients[id]
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	timestamp := r.Header.Get(TimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if skew := h.now().Sub(time.Unix(unix, 0)); skew > MaxClockSkew || skew < -MaxClockSkew {
+		return nil, ErrInvalidCredentials
+	}
+
+	var body []byte
+	if r.Body != nil {
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	expected := Sign(client.Secret, r.Method, r.URL.RequestURI(), timestamp, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Name: client.ID, Scopes: client.Scopes}, nil
+}
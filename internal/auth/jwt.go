@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/axidex/elliptic/config"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWT authenticates bearer tokens signed with ES256, ES384 or ES512 by one of
+// the configured EC keys. The space-separated "scope" claim lists the
+// granted scopes and "sub" names the principal.
+type JWT struct {
+	keys   []*ecdsa.PublicKey
+	parser *jwt.Parser
+}
+
+type claims struct {
+	Scope string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// NewJWT loads the verification keys: project public keys in PEM files.
+func NewJWT(cfg config.JWT) (*JWT, error) {
+	j := &JWT{}
+	for _, path := range cfg.PublicKeys {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		pub, err := cypher.ImportPublicPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		j.keys = append(j.keys, pub.ExportECDSA())
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	j.parser = jwt.NewParser(options...)
+	return j, nil
+}
+
+func (j *JWT) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	var c claims
+	_, err := j.parser.ParseWithClaims(token, &c, func(*jwt.Token) (any, error) {
+		keys := jwt.VerificationKeySet{}
+		for _, key := range j.keys {
+			keys.Keys = append(keys.Keys, key)
+		}
+		return keys, nil
+	})
+	if err != nil || c.Subject == "" {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Name: c.Subject, Scopes: strings.Fields(c.Scope)}, nil
+}
+
+// IssueToken signs a token for subject with the given scopes using a project
+// private key, with the issuer and audience from cfg. The algorithm follows
+// the curve: ES256 for P-256, ES384 for P-384 and ES512 for P-521.
+func IssueToken(prv *cypher.PrivateKey, cfg config.JWT, subject string, scopes []string, ttl time.Duration) (string, error) {
+	var method jwt.SigningMethod
+	switch prv.Curve {
+	case elliptic.P256():
+		method = jwt.SigningMethodES256
+	case elliptic.P384():
+		method = jwt.SigningMethodES384
+	case elliptic.P521():
+		method = jwt.SigningMethodES512
+	default:
+		return "", cypher.ErrInvalidCurve
+	}
+
+	now := time.Now()
+	registered := jwt.RegisteredClaims{
+		Issuer:    cfg.Issuer,
+		Subject:   subject,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+	if cfg.Audience != "" {
+		registered.Audience = jwt.ClaimStrings{cfg.Audience}
+	}
+	return jwt.NewWithClaims(method, claims{
+		Scope:            strings.Join(scopes, " "),
+		RegisteredClaims: registered,
+	}).SignedString(prv.ExportECDSA())
+}
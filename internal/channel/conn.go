@@ -0,0 +1,235 @@
+package channel
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/axidex/elliptic/internal/cypher"
+)
+
+// MaxRecordSize is the largest plaintext carried by a single record.
+const MaxRecordSize = 16 * 1024
+
+var (
+	ErrNoPeerKey    = errors.New("channel: peer key is required to dial")
+	ErrRecordTooBig = errors.New("channel: record too large")
+	ErrBadRecord    = errors.New("channel: record authentication failed")
+)
+
+// Config holds the static keys of one side of a channel.
+type Config struct {
+	// PrivateKey is the static key of this side.
+	PrivateKey *cypher.PrivateKey
+	// PeerKey is the static key of the server. It is required for clients.
+	PeerKey *cypher.PublicKey
+	// VerifyPeer is called by servers with the client's static key and may
+	// reject it. If nil, every client is accepted.
+	VerifyPeer func(*cypher.PublicKey) error
+}
+
+// ConfigFromPEM builds a Config from a PEM private key and an optional PEM
+// public key of the peer.
+func ConfigFromPEM(privatePEM, peerPEM []byte) (*Config, error) {
+	prv, err := cypher.ImportPrivatePEM(privatePEM)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{PrivateKey: prv}
+	if peerPEM != nil {
+		if config.PeerKey, err = cypher.ImportPublicPEM(peerPEM); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// Conn is a secure channel over a net.Conn. The handshake runs on the first
+// Read or Write, or when Handshake is called. After that every Write is sent
+// as one or more records sealed with AES-GCM under the key of its direction.
+//
+// A record is a 2-byte big-endian length followed by the sealed payload. The
+// nonce is the record sequence number, so records can't be replayed,
+// reordered or dropped without the next Read failing.
+type Conn struct {
+	net.Conn
+	config   *Config
+	isClient bool
+
+	handshakeMu  sync.Mutex
+	handshakeErr error
+	handshake    *cypher.Handshake
+
+	in      sync.Mutex
+	recv    cipher.AEAD
+	recvSeq uint64
+	rbuf    []byte
+
+	out     sync.Mutex
+	send    cipher.AEAD
+	sendSeq uint64
+}
+
+// Client returns a channel on the client side of conn.
+func Client(conn net.Conn, config *Config) *Conn {
+	return &Conn{Conn: conn, config: config, isClient: true}
+}
+
+// Server returns a channel on the server side of conn.
+func Server(conn net.Conn, config *Config) *Conn {
+	return &Conn{Conn: conn, config: config}
+}
+
+// Handshake runs the key exchange if it has not run yet.
+func (c *Conn) Handshake() error {
+	c.handshakeMu.Lock()
+	defer c.handshakeMu.Unlock()
+
+	if c.handshake != nil || c.handshakeErr != nil {
+		return c.handshakeErr
+	}
+	c.handshakeErr = c.runHandshake()
+	return c.handshakeErr
+}
+
+func (c *Conn) runHandshake() (err error) {
+	if c.isClient {
+		if c.config.PeerKey == nil {
+			return ErrNoPeerKey
+		}
+		c.handshake, err = cypher.NewInitiator(rand.Reader, c.config.PrivateKey, c.config.PeerKey)
+	} else {
+		c.handshake, err = cypher.NewResponder(rand.Reader, c.config.PrivateKey, c.config.VerifyPeer)
+	}
+	if err != nil {
+		return
+	}
+
+	// The client writes the first and the last message.
+	for writing := c.isClient; !c.handshake.Complete(); writing = !writing {
+		if writing {
+			var msg []byte
+			if msg, err = c.handshake.WriteMessage(); err != nil {
+				return
+			}
+			err = c.writeFrame(msg)
+		} else {
+			var msg []byte
+			if msg, err = c.readFrame(); err != nil {
+				return
+			}
+			err = c.handshake.ReadMessage(msg)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	sendKey, recvKey, err := c.handshake.SessionKeys()
+	if err != nil {
+		return
+	}
+	if c.send, err = newAEAD(sendKey); err != nil {
+		return
+	}
+	c.recv, err = newAEAD(recvKey)
+	return
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// PeerKey returns the authenticated static key of the other side, or nil
+// before the handshake.
+func (c *Conn) PeerKey() *cypher.PublicKey {
+	if c.Handshake() != nil {
+		return nil
+	}
+	return c.handshake.PeerStatic()
+}
+
+// Read reads decrypted application data.
+func (c *Conn) Read(b []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+	c.in.Lock()
+	defer c.in.Unlock()
+
+	for len(c.rbuf) == 0 {
+		record, err := c.readFrame()
+		if err != nil {
+			return 0, err
+		}
+		if c.rbuf, err = c.recv.Open(record[:0], nonce(c.recvSeq), record, nil); err != nil {
+			return 0, ErrBadRecord
+		}
+		c.recvSeq++
+	}
+	n := copy(b, c.rbuf)
+	c.rbuf = c.rbuf[n:]
+	return n, nil
+}
+
+// Write encrypts b and sends it in records of at most MaxRecordSize bytes.
+func (c *Conn) Write(b []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+	c.out.Lock()
+	defer c.out.Unlock()
+
+	var n int
+	for len(b) > 0 {
+		chunk := b[:min(len(b), MaxRecordSize)]
+		record := c.send.Seal(nil, nonce(c.sendSeq), chunk, nil)
+		if err := c.writeFrame(record); err != nil {
+			return n, err
+		}
+		c.sendSeq++
+		n += len(chunk)
+		b = b[len(chunk):]
+	}
+	return n, nil
+}
+
+func nonce(seq uint64) []byte {
+	n := make([]byte, 12)
+	binary.BigEndian.PutUint64(n[4:], seq)
+	return n
+}
+
+func (c *Conn) writeFrame(payload []byte) error {
+	if len(payload) > 0xffff {
+		return ErrRecordTooBig
+	}
+	frame := make([]byte, 2, 2+len(payload))
+	binary.BigEndian.PutUint16(frame, uint16(len(payload)))
+	_, err := c.Conn.Write(append(frame, payload...))
+	return err
+}
+
+func (c *Conn) readFrame() ([]byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.Conn, header[:]); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint16(header[:]))
+	if _, err := io.ReadFull(c.Conn, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,171 @@
+package channel
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/axidex/elliptic/internal/cypher"
+)
+
+// testKeyPEM generates a key and returns it as PEM private and public keys.
+func testKeyPEM(t *testing.T) (private, public []byte) {
+	t.Helper()
+	prv, err := cypher.GenerateKey(rand.Reader, cypher.DefaultCurve, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if private, err = cypher.ExportPrivatePEM(prv); err != nil {
+		t.Fatal(err)
+	}
+	if public, err = cypher.ExportPublicPEM(&prv.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	return private, public
+}
+
+func testConfig(t *testing.T, privatePEM, peerPEM []byte) *Config {
+	t.Helper()
+	config, err := ConfigFromPEM(privatePEM, peerPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+// listen starts a loopback listener that passes each accepted connection to
+// serve and closes it afterwards.
+func listen(t *testing.T, config *Config, serve func(*Conn)) net.Listener {
+	t.Helper()
+	l, err := Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serve(conn.(*Conn))
+			}()
+		}
+	}()
+	return l
+}
+
+func TestLoopback(t *testing.T) {
+	serverPrivate, serverPublic := testKeyPEM(t)
+	clientPrivate, _ := testKeyPEM(t)
+
+	greeting := bytes.Repeat([]byte("server"), MaxRecordSize/3)
+	l := listen(t, testConfig(t, serverPrivate, nil), func(conn *Conn) {
+		if _, err := conn.Write(greeting); err != nil {
+			return
+		}
+		io.Copy(conn, conn)
+	})
+
+	conn, err := Dial("tcp", l.Addr().String(), testConfig(t, clientPrivate, serverPublic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	got := make([]byte, len(greeting))
+	if _, err = io.ReadFull(conn, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, greeting) {
+		t.Fatal("server data corrupted")
+	}
+
+	for _, size := range []int{1, 100, MaxRecordSize, 3*MaxRecordSize + 7} {
+		msg := make([]byte, size)
+		rand.Read(msg)
+		if _, err = conn.Write(msg); err != nil {
+			t.Fatal(err)
+		}
+		echo := make([]byte, size)
+		if _, err = io.ReadFull(conn, echo); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(echo, msg) {
+			t.Fatalf("echo of %d bytes corrupted", size)
+		}
+	}
+}
+
+func TestPeerKey(t *testing.T) {
+	serverPrivate, serverPublic := testKeyPEM(t)
+	clientPrivate, clientPublic := testKeyPEM(t)
+
+	peers := make(chan *cypher.PublicKey, 1)
+	l := listen(t, testConfig(t, serverPrivate, nil), func(conn *Conn) {
+		peers <- conn.PeerKey()
+	})
+
+	conn, err := Dial("tcp", l.Addr().String(), testConfig(t, clientPrivate, serverPublic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	expected, err := cypher.ImportPublicPEM(clientPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer := <-peers
+	if peer == nil || peer.X.Cmp(expected.X) != 0 || peer.Y.Cmp(expected.Y) != 0 {
+		t.Error("server didn't authenticate the client's key")
+	}
+}
+
+func TestWrongPeerKey(t *testing.T) {
+	serverPrivate, _ := testKeyPEM(t)
+	clientPrivate, _ := testKeyPEM(t)
+	_, otherPublic := testKeyPEM(t)
+
+	l := listen(t, testConfig(t, serverPrivate, nil), func(conn *Conn) {
+		conn.Handshake()
+	})
+
+	conn, err := Dial("tcp", l.Addr().String(), testConfig(t, clientPrivate, otherPublic))
+	if err == nil {
+		conn.Close()
+		t.Fatal("handshake with the wrong server key succeeded")
+	}
+}
+
+func TestTamperedRecord(t *testing.T) {
+	serverPrivate, serverPublic := testKeyPEM(t)
+	clientPrivate, _ := testKeyPEM(t)
+
+	errs := make(chan error, 1)
+	l := listen(t, testConfig(t, serverPrivate, nil), func(conn *Conn) {
+		_, err := conn.Read(make([]byte, 64))
+		errs <- err
+	})
+
+	conn, err := Dial("tcp", l.Addr().String(), testConfig(t, clientPrivate, serverPublic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	record := conn.send.Seal(nil, nonce(conn.sendSeq), []byte("hello"), nil)
+	record[0] ^= 1
+	if err = conn.writeFrame(record); err != nil {
+		t.Fatal(err)
+	}
+	if err = <-errs; !errors.Is(err, ErrBadRecord) {
+		t.Fatalf("got %v, want %v", err, ErrBadRecord)
+	}
+}
@@ -0,0 +1,52 @@
+package channel
+
+import (
+	"net"
+)
+
+type listener struct {
+	net.Listener
+	config *Config
+}
+
+// Accept waits for the next connection and returns it as a server side
+// *Conn. The handshake runs on the first Read or Write.
+func (l *listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return Server(conn, l.config), nil
+}
+
+// NewListener wraps inner so accepted connections are secure channels.
+func NewListener(inner net.Listener, config *Config) net.Listener {
+	return &listener{Listener: inner, config: config}
+}
+
+// Listen announces on the local address and accepts secure channels.
+func Listen(network, address string, config *Config) (net.Listener, error) {
+	inner, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewListener(inner, config), nil
+}
+
+// Dial connects to the address and completes the handshake before returning.
+func Dial(network, address string, config *Config) (*Conn, error) {
+	if config.PeerKey == nil {
+		return nil, ErrNoPeerKey
+	}
+	raw, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := Client(raw, config)
+	if err = conn.Handshake(); err != nil {
+		raw.Close()
+		return nil, err
+	}
+	return conn, nil
+}
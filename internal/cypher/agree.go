@@ -0,0 +1,32 @@
+package cypher
+
+import (
+	"crypto/hkdf"
+	"fmt"
+)
+
+var (
+	ErrInvalidKeyLength = fmt.Errorf("ecies: invalid derived key length")
+)
+
+// Agree runs ECDH between prv and the peer public key and derives a key of
+// the given length from the shared secret with HKDF (RFC 5869). The hash of
+// the key's ECIES parameters is used; salt and info are optional.
+func (prv *PrivateKey) Agree(pub *PublicKey, length int, salt, info []byte) (key []byte, err error) {
+	params := paramsOf(&prv.PublicKey)
+	if params == nil {
+		return nil, ErrUnsupportedECIESParameters
+	}
+	if length <= 0 || length > 255*params.Hash().Size() {
+		return nil, ErrInvalidKeyLength
+	}
+	if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, ErrInvalidPublicKey
+	}
+
+	z, err := prv.GenerateShared(pub, 0, 0)
+	if err != nil {
+		return
+	}
+	return hkdf.Key(params.Hash, z, salt, string(info), length)
+}
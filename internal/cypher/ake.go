@@ -0,0 +1,379 @@
+package cypher
+
+import (
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrHandshakeState  = fmt.Errorf("ecies: handshake message out of order")
+	ErrHandshakeFailed = fmt.Errorf("ecies: handshake failed")
+	ErrUnknownPeer     = fmt.Errorf("ecies: peer static key is not trusted")
+)
+
+const akeProtocol = "ECIES-AKE-v1"
+
+// Handshake is one side of a mutually authenticated key exchange on the
+// project's static keys. It follows the Noise IK pattern: the initiator knows
+// the responder's static key in advance and sends its own static key
+// encrypted in the first message.
+//
+//	-> e, es, s, ss
+//	<- e, ee, se, confirm
+//	-> confirm
+//
+// Every message is mixed into a transcript hash, and every Diffie-Hellman
+// result into a chaining key with HKDF. The confirmation messages are HMACs
+// of the transcript under keys derived from the final chaining key, so each
+// side proves it holds its static private key and saw the same transcript.
+//
+// Both static keys must use the same curve and ECIES parameters. Messages are
+// produced with WriteMessage and consumed with ReadMessage, alternating in the
+// order above; the initiator writes first. Any error aborts the handshake.
+type Handshake struct {
+	initiator bool
+	step      int
+	err       error
+	rand      io.Reader
+	params    *ECIESParams
+
+	static, ephemeral *PrivateKey
+	peer, peerEph     *PublicKey
+	verifyPeer        func(*PublicKey) error
+
+	h, ck []byte
+}
+
+// NewInitiator starts a handshake with the responder owning peer.
+func NewInitiator(rand io.Reader, static *PrivateKey, peer *PublicKey) (*Handshake, error) {
+	hs, err := newHandshake(rand, static, true)
+	if err != nil {
+		return nil, err
+	}
+	if peer.Curve != static.Curve {
+		return nil, ErrInvalidCurve
+	}
+	if params := paramsOf(peer); params == nil || !sameParams(params, hs.params) {
+		return nil, ErrInvalidParams
+	}
+	hs.peer = peer
+	hs.mixHash(elliptic.Marshal(peer.Curve, peer.X, peer.Y))
+	return hs, nil
+}
+
+// NewResponder waits for a handshake from any initiator. verifyPeer is called
+// with the initiator's static key once it is known and may reject it; nil
+// accepts every key.
+func NewResponder(rand io.Reader, static *PrivateKey, verifyPeer func(*PublicKey) error) (*Handshake, error) {
+	hs, err := newHandshake(rand, static, false)
+	if err != nil {
+		return nil, err
+	}
+	hs.verifyPeer = verifyPeer
+	hs.mixHash(elliptic.Marshal(static.Curve, static.X, static.Y))
+	return hs, nil
+}
+
+func newHandshake(rand io.Reader, static *PrivateKey, initiator bool) (*Handshake, error) {
+	params := paramsOf(&static.PublicKey)
+	if params == nil {
+		return nil, ErrUnsupportedECIESParameters
+	}
+	hs := &Handshake{
+		initiator: initiator,
+		rand:      rand,
+		params:    params,
+		static:    static,
+	}
+
+	curve, ok := LookupCurve(static.Curve)
+	if !ok {
+		return nil, ErrInvalidCurve
+	}
+	hs.h = hs.hash([]byte(akeProtocol), []byte(curve.Name))
+	hs.ck = hs.h
+	return hs, nil
+}
+
+// Complete reports whether the handshake has finished on this side.
+func (hs *Handshake) Complete() bool {
+	return hs.step == 3
+}
+
+// PeerStatic returns the authenticated static key of the other side. For the
+// responder it is nil until the first message has been read.
+func (hs *Handshake) PeerStatic() *PublicKey {
+	return hs.peer
+}
+
+// Transcript returns the hash binding all handshake messages and both static
+// keys. It can be used as a channel binding once the handshake is complete.
+func (hs *Handshake) Transcript() []byte {
+	return append([]byte(nil), hs.h...)
+}
+
+// SessionKeys returns the keys for traffic sent and received by this side,
+// each params.KeyLen bytes long.
+func (hs *Handshake) SessionKeys() (send, recv []byte, err error) {
+	if !hs.Complete() {
+		return nil, nil, ErrHandshakeState
+	}
+	i2r, err := hs.expand("initiator to responder", hs.params.KeyLen)
+	if err != nil {
+		return
+	}
+	r2i, err := hs.expand("responder to initiator", hs.params.KeyLen)
+	if err != nil {
+		return
+	}
+	if hs.initiator {
+		return i2r, r2i, nil
+	}
+	return r2i, i2r, nil
+}
+
+// WriteMessage produces the next handshake message of this side.
+func (hs *Handshake) WriteMessage() (msg []byte, err error) {
+	if hs.err != nil {
+		return nil, hs.err
+	}
+	if hs.Complete() || (hs.step%2 == 0) != hs.initiator {
+		return nil, ErrHandshakeState
+	}
+
+	switch hs.step {
+	case 0:
+		msg, err = hs.writeInit()
+	case 1:
+		msg, err = hs.writeResponse()
+	case 2:
+		msg, err = hs.confirm("initiator confirm")
+	}
+	if err != nil {
+		hs.err = err
+		return nil, err
+	}
+	hs.step++
+	return msg, nil
+}
+
+// ReadMessage consumes the next handshake message of the other side.
+func (hs *Handshake) ReadMessage(msg []byte) (err error) {
+	if hs.err != nil {
+		return hs.err
+	}
+	if hs.Complete() || (hs.step%2 == 0) == hs.initiator {
+		return ErrHandshakeState
+	}
+
+	switch hs.step {
+	case 0:
+		err = hs.readInit(msg)
+	case 1:
+		err = hs.readResponse(msg)
+	case 2:
+		err = hs.checkConfirm("initiator confirm", msg)
+	}
+	if err != nil {
+		hs.err = err
+		return err
+	}
+	hs.step++
+	return nil
+}
+
+// -> e, es, s, ss
+func (hs *Handshake) writeInit() (msg []byte, err error) {
+	hs.ephemeral, err = GenerateKey(hs.rand, hs.static.Curve, hs.params)
+	if err != nil {
+		return
+	}
+	e := elliptic.Marshal(hs.static.Curve, hs.ephemeral.X, hs.ephemeral.Y)
+	hs.mixHash(e)
+	if err = hs.mixDH(hs.ephemeral, hs.peer); err != nil {
+		return
+	}
+
+	s, err := hs.seal(elliptic.Marshal(hs.static.Curve, hs.static.X, hs.static.Y))
+	if err != nil {
+		return
+	}
+	hs.mixHash(s)
+	if err = hs.mixDH(hs.static, hs.peer); err != nil {
+		return
+	}
+	return append(e, s...), nil
+}
+
+func (hs *Handshake) readInit(msg []byte) (err error) {
+	curve := hs.static.Curve
+	pointLen := 2*((curve.Params().BitSize+7)/8) + 1
+	if len(msg) <= pointLen {
+		return ErrHandshakeFailed
+	}
+	if hs.peerEph, err = hs.parsePoint(msg[:pointLen]); err != nil {
+		return
+	}
+	hs.mixHash(msg[:pointLen])
+	if err = hs.mixDH(hs.static, hs.peerEph); err != nil {
+		return
+	}
+
+	s, err := hs.open(msg[pointLen:])
+	if err != nil {
+		return
+	}
+	if hs.peer, err = hs.parsePoint(s); err != nil {
+		return
+	}
+	if hs.verifyPeer != nil {
+		if err = hs.verifyPeer(hs.peer); err != nil {
+			return
+		}
+	}
+	hs.mixHash(msg[pointLen:])
+	return hs.mixDH(hs.static, hs.peer)
+}
+
+// <- e, ee, se, confirm
+func (hs *Handshake) writeResponse() (msg []byte, err error) {
+	hs.ephemeral, err = GenerateKey(hs.rand, hs.static.Curve, hs.params)
+	if err != nil {
+		return
+	}
+	e := elliptic.Marshal(hs.static.Curve, hs.ephemeral.X, hs.ephemeral.Y)
+	hs.mixHash(e)
+	if err = hs.mixDH(hs.ephemeral, hs.peerEph); err != nil {
+		return
+	}
+	if err = hs.mixDH(hs.ephemeral, hs.peer); err != nil {
+		return
+	}
+
+	tag, err := hs.confirm("responder confirm")
+	if err != nil {
+		return
+	}
+	return append(e, tag...), nil
+}
+
+func (hs *Handshake) readResponse(msg []byte) (err error) {
+	curve := hs.static.Curve
+	pointLen := 2*((curve.Params().BitSize+7)/8) + 1
+	if len(msg) != pointLen+hs.params.Hash().Size() {
+		return ErrHandshakeFailed
+	}
+	if hs.peerEph, err = hs.parsePoint(msg[:pointLen]); err != nil {
+		return
+	}
+	hs.mixHash(msg[:pointLen])
+	if err = hs.mixDH(hs.ephemeral, hs.peerEph); err != nil {
+		return
+	}
+	if err = hs.mixDH(hs.static, hs.peerEph); err != nil {
+		return
+	}
+	return hs.checkConfirm("responder confirm", msg[pointLen:])
+}
+
+// confirm computes the key confirmation tag of one side over the transcript
+// so far and mixes it into the transcript.
+func (hs *Handshake) confirm(label string) ([]byte, error) {
+	key, err := hs.expand(label, hs.params.Hash().Size())
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(hs.params.Hash, key)
+	mac.Write(hs.h)
+	tag := mac.Sum(nil)
+	hs.mixHash(tag)
+	return tag, nil
+}
+
+func (hs *Handshake) checkConfirm(label string, tag []byte) error {
+	expected, err := hs.confirm(label)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(tag, expected) {
+		return ErrHandshakeFailed
+	}
+	return nil
+}
+
+func (hs *Handshake) parsePoint(b []byte) (*PublicKey, error) {
+	curve := hs.static.Curve
+	x, y := elliptic.Unmarshal(curve, b)
+	if x == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return &PublicKey{X: x, Y: y, Curve: curve, Params: hs.params}, nil
+}
+
+func (hs *Handshake) hash(data ...[]byte) []byte {
+	h := hs.params.Hash()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func (hs *Handshake) mixHash(data []byte) {
+	hs.h = hs.hash(hs.h, data)
+}
+
+// mixDH feeds the Diffie-Hellman result of prv and pub into the chaining key.
+func (hs *Handshake) mixDH(prv *PrivateKey, pub *PublicKey) error {
+	z, err := prv.GenerateShared(pub, 0, 0)
+	if err != nil {
+		return err
+	}
+	prk, err := hkdf.Extract(hs.params.Hash, z, hs.ck)
+	if err != nil {
+		return err
+	}
+	hs.ck, err = hkdf.Expand(hs.params.Hash, prk, "chaining key", hs.params.Hash().Size())
+	return err
+}
+
+func (hs *Handshake) expand(label string, length int) ([]byte, error) {
+	return hkdf.Expand(hs.params.Hash, hs.ck, label, length)
+}
+
+// aead returns the cipher for the encrypted static key. The key is used for a
+// single message, so the nonce is all zeros.
+func (hs *Handshake) aead() (cipher.AEAD, error) {
+	key, err := hs.expand("static key", hs.params.KeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := hs.params.Cipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (hs *Handshake) seal(plaintext []byte) ([]byte, error) {
+	aead, err := hs.aead()
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, make([]byte, aead.NonceSize()), plaintext, hs.h), nil
+}
+
+func (hs *Handshake) open(ciphertext []byte) ([]byte, error) {
+	aead, err := hs.aead()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext, hs.h)
+	if err != nil {
+		return nil, ErrHandshakeFailed
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,144 @@
+package cypher
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func generateTestKey(t *testing.T) *PrivateKey {
+	t.Helper()
+	prv, err := GenerateKey(rand.Reader, DefaultCurve, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prv
+}
+
+// handshakePair starts an initiator that expects peer as the responder's
+// static key and a responder on responderKey.
+func handshakePair(t *testing.T, initiatorKey, responderKey *PrivateKey, peer *PublicKey, verifyPeer func(*PublicKey) error) (*Handshake, *Handshake) {
+	t.Helper()
+	initiator, err := NewInitiator(rand.Reader, initiatorKey, peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responder, err := NewResponder(rand.Reader, responderKey, verifyPeer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return initiator, responder
+}
+
+// runHandshake passes the three messages between both sides, calling tamper
+// on each message before it is read. It returns the first error.
+func runHandshake(initiator, responder *Handshake, tamper func(i int, msg []byte)) error {
+	writers := []*Handshake{initiator, responder, initiator}
+	readers := []*Handshake{responder, initiator, responder}
+	for i := range writers {
+		msg, err := writers[i].WriteMessage()
+		if err != nil {
+			return err
+		}
+		if tamper != nil {
+			tamper(i, msg)
+		}
+		if err = readers[i].ReadMessage(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestHandshake(t *testing.T) {
+	initiatorKey, responderKey := generateTestKey(t), generateTestKey(t)
+	initiator, responder := handshakePair(t, initiatorKey, responderKey, &responderKey.PublicKey, nil)
+
+	if err := runHandshake(initiator, responder, nil); err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	if !initiator.Complete() || !responder.Complete() {
+		t.Fatal("handshake is not complete")
+	}
+
+	iSend, iRecv, err := initiator.SessionKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rSend, rRecv, err := responder.SessionKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(iSend, rRecv) || !bytes.Equal(iRecv, rSend) {
+		t.Error("session keys don't match")
+	}
+	if bytes.Equal(iSend, iRecv) {
+		t.Error("send and receive keys are equal")
+	}
+	if !bytes.Equal(initiator.Transcript(), responder.Transcript()) {
+		t.Error("transcripts differ")
+	}
+
+	peer := responder.PeerStatic()
+	if peer == nil || peer.X.Cmp(initiatorKey.X) != 0 || peer.Y.Cmp(initiatorKey.Y) != 0 {
+		t.Error("responder didn't learn the initiator's static key")
+	}
+}
+
+func TestHandshakeTampered(t *testing.T) {
+	for i, name := range []string{"init", "response", "confirm"} {
+		t.Run(name, func(t *testing.T) {
+			initiatorKey, responderKey := generateTestKey(t), generateTestKey(t)
+			initiator, responder := handshakePair(t, initiatorKey, responderKey, &responderKey.PublicKey, nil)
+
+			err := runHandshake(initiator, responder, func(j int, msg []byte) {
+				if j == i {
+					msg[len(msg)-1] ^= 1
+				}
+			})
+			if err == nil {
+				t.Fatal("tampered handshake succeeded")
+			}
+		})
+	}
+}
+
+func TestHandshakeWrongResponderKey(t *testing.T) {
+	initiatorKey, responderKey, otherKey := generateTestKey(t), generateTestKey(t), generateTestKey(t)
+	initiator, responder := handshakePair(t, initiatorKey, responderKey, &otherKey.PublicKey, nil)
+
+	if err := runHandshake(initiator, responder, nil); !errors.Is(err, ErrHandshakeFailed) {
+		t.Fatalf("got %v, want %v", err, ErrHandshakeFailed)
+	}
+}
+
+func TestHandshakeRejectedPeer(t *testing.T) {
+	initiatorKey, responderKey := generateTestKey(t), generateTestKey(t)
+	reject := func(*PublicKey) error { return ErrUnknownPeer }
+	initiator, responder := handshakePair(t, initiatorKey, responderKey, &responderKey.PublicKey, reject)
+
+	if err := runHandshake(initiator, responder, nil); !errors.Is(err, ErrUnknownPeer) {
+		t.Fatalf("got %v, want %v", err, ErrUnknownPeer)
+	}
+	if responder.Complete() {
+		t.Error("responder completed a rejected handshake")
+	}
+}
+
+func TestHandshakeConfirmMismatch(t *testing.T) {
+	initiatorKey, responderKey := generateTestKey(t), generateTestKey(t)
+	initiator, responder := handshakePair(t, initiatorKey, responderKey, &responderKey.PublicKey, nil)
+	if err := runHandshake(initiator, responder, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := initiator.confirm("initiator confirm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag[0] ^= 1
+	if err = responder.checkConfirm("initiator confirm", tag); !errors.Is(err, ErrHandshakeFailed) {
+		t.Fatalf("got %v, want %v", err, ErrHandshakeFailed)
+	}
+}
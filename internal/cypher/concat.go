@@ -1,14 +1,8 @@
 package cypher
 
 import (
-	"fmt"
 	"hash"
-	"math/big"
-)
-
-var (
-	big2To32   = new(big.Int).Exp(big.NewInt(2), big.NewInt(32), nil)
-	big2To32M1 = new(big.Int).Sub(big2To32, big.NewInt(1))
+	"math"
 )
 
 func incCounter(ctr []byte) {
@@ -30,15 +24,15 @@ func concatKDF(hash hash.Hash, z, s1 []byte, kdLen int) (k []byte, err error) {
 		s1 = make([]byte, 0)
 	}
 
-	reps := ((kdLen + 7) * 8) / (hash.BlockSize() * 8)
-	if big.NewInt(int64(reps)).Cmp(big2To32M1) > 0 {
-		fmt.Println(big2To32M1)
+	// One block of hash output per counter value, the counter is 32 bits.
+	reps := (kdLen + hash.Size() - 1) / hash.Size()
+	if uint64(reps) > math.MaxUint32 {
 		return nil, ErrKeyDataTooLong
 	}
 
 	counter := []byte{0, 0, 0, 1}
 	k = make([]byte, 0)
-	for i := 0; i <= reps; i++ {
+	for i := 0; i < reps; i++ {
 		hash.Write(counter)
 		hash.Write(z)
 		hash.Write(s1)
@@ -0,0 +1,81 @@
+package cypher
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math"
+	"testing"
+)
+
+// referenceKDF computes the concatenation KDF block by block, straight from
+// SP 800-56A: Hash(counter || Z || OtherInfo) for counters 1, 2, ...
+func referenceKDF(newHash func() hash.Hash, z, s1 []byte, kdLen int) []byte {
+	var k []byte
+	for counter := uint32(1); len(k) < kdLen; counter++ {
+		h := newHash()
+		binary.Write(h, binary.BigEndian, counter)
+		h.Write(z)
+		h.Write(s1)
+		k = h.Sum(k)
+	}
+	return k[:kdLen]
+}
+
+func TestConcatKDF(t *testing.T) {
+	z := []byte("shared secret")
+	s1 := []byte("other info")
+
+	for _, tt := range []struct {
+		name    string
+		newHash func() hash.Hash
+	}{
+		{"SHA-256", sha256.New},
+		{"SHA-512", sha512.New},
+	} {
+		size := tt.newHash().Size()
+		for _, kdLen := range []int{1, size - 1, size, size + 1, 3 * size / 2, 2 * size, 3*size + 5, 10 * size} {
+			k, err := concatKDF(tt.newHash(), z, s1, kdLen)
+			if err != nil {
+				t.Fatalf("%s, %d bytes: %v", tt.name, kdLen, err)
+			}
+			if len(k) != kdLen {
+				t.Fatalf("%s: got %d bytes, want %d", tt.name, len(k), kdLen)
+			}
+			if want := referenceKDF(tt.newHash, z, s1, kdLen); !bytes.Equal(k, want) {
+				t.Errorf("%s, %d bytes: output differs from the reference", tt.name, kdLen)
+			}
+		}
+	}
+}
+
+func TestConcatKDFPrefix(t *testing.T) {
+	short, err := concatKDF(sha256.New(), []byte("z"), nil, sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long, err := concatKDF(sha256.New(), []byte("z"), nil, 4*sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(long, short) {
+		t.Error("longer output doesn't extend the shorter one")
+	}
+	if bytes.Equal(long[:sha256.Size], long[sha256.Size:2*sha256.Size]) {
+		t.Error("counter doesn't change between blocks")
+	}
+}
+
+func TestConcatKDFTooLong(t *testing.T) {
+	if math.MaxInt/sha256.Size <= math.MaxUint32 {
+		t.Skip("int is too small to request more than 2^32 blocks")
+	}
+	blocks := uint64(math.MaxUint32) + 1
+	kdLen := int(blocks * sha256.Size)
+	if _, err := concatKDF(sha256.New(), []byte("z"), nil, kdLen); !errors.Is(err, ErrKeyDataTooLong) {
+		t.Fatalf("got %v, want %v", err, ErrKeyDataTooLong)
+	}
+}
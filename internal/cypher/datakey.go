@@ -0,0 +1,40 @@
+package cypher
+
+import (
+	"io"
+)
+
+// GenerateDataKey creates a random symmetric key of the given size for
+// envelope encryption. It returns the key itself and the key encrypted to
+// pub with ECIES; only the latter should be stored.
+func GenerateDataKey(rand io.Reader, pub *PublicKey, size int) (key []byte, wrapped string, err error) {
+	switch size {
+	case 16, 24, 32:
+	default:
+		return nil, "", ErrInvalidKeyLength
+	}
+
+	key = make([]byte, size)
+	if _, err = io.ReadFull(rand, key); err != nil {
+		return nil, "", err
+	}
+	wrapped, err = Encrypt(rand, pub, key, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, wrapped, nil
+}
+
+// UnwrapDataKey decrypts a data key produced by GenerateDataKey.
+func (prv *PrivateKey) UnwrapDataKey(rand io.Reader, wrapped string) ([]byte, error) {
+	key, err := prv.Decrypt(rand, wrapped, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, ErrInvalidKeyLength
+	}
+}
@@ -1,6 +1,7 @@
 package cypher
 
 import (
+	"context"
 	"crypto/cipher"
 	"crypto/elliptic"
 	"crypto/hmac"
@@ -18,6 +19,7 @@ var (
 	ErrInvalidPublicKey           = fmt.Errorf("ecies: invalid public key")
 	ErrSharedKeyIsPointAtInfinity = fmt.Errorf("ecies: shared key is point at infinity")
 	ErrSharedKeyTooBig            = fmt.Errorf("ecies: shared key params are too big")
+	ErrEmptyMessage               = fmt.Errorf("ecies: message is empty")
 )
 
 // messageTag computes the MAC of a message (called the tag) as per
@@ -74,119 +76,265 @@ func symDecrypt(rand io.Reader, params *ECIESParams, key, ct []byte) (m []byte,
 	return
 }
 
-func Encrypt(rand io.Reader, pub *PublicKey, m, s1, s2 []byte) (ctBase64 string, err error) {
-	params := pub.Params
-	if params == nil {
-		fmt.Println("Params is nil! Generating params from curve")
-		if params = ParamsFromCurve(pub.Curve); params == nil {
-			err = ErrUnsupportedECIESParameters
-			return
-		}
-	}
-	R, err := GenerateKey(rand, pub.Curve, params)
-	if err != nil {
-		return
-	}
+// Ciphertext versions. A plain ECIES ciphertext starts with the ephemeral
+// public point, so its first byte is 2, 3 or 4; ciphertexts of the other
+// modes start with their own version byte instead.
+const (
+	versionReencrypted byte = 0x10
+	versionHybrid      byte = 0x20
+	versionElGamal     byte = 0x30
+	versionMV          byte = 0x31
+)
 
+// deriveKeys runs the KDF over the shared secret and splits the result into
+// the encryption and MAC keys.
+func deriveKeys(ctx context.Context, params *ECIESParams, z, s1 []byte, tr *Trace) (Ke, Km []byte, err error) {
 	hash := params.Hash()
-	z, err := R.GenerateShared(pub, params.KeyLen, params.MacLen)
-	if err != nil {
-		return
-	}
+	_, span := tracer.Start(ctx, "ecies.concatKDF")
 	K, err := concatKDF(hash, z, s1, params.KeyLen+params.MacLen)
+	endSpan(span, err)
 	if err != nil {
 		return
 	}
-	Ke := K[:params.KeyLen]
-	Km := K[params.MacLen:]
+	tr.record("K", K, true)
+	Ke = K[:params.KeyLen]
+	Km = K[params.MacLen:]
 	hash.Write(Km)
 	Km = hash.Sum(nil)
 	hash.Reset()
+	tr.record("Ke", Ke, true)
+	tr.record("Km", Km, true)
+	return
+}
 
-	em, err := symEncrypt(rand, params, Ke, m)
-	if err != nil || len(em) <= params.BlockSize {
+// sealMessage encrypts and authenticates m under the shared secret z,
+// returning the symmetric ciphertext followed by its tag. An empty m is
+// refused, since openMessage rejects a message without a body.
+func sealMessage(ctx context.Context, rand io.Reader, params *ECIESParams, z, m, s1, s2 []byte, tr *Trace) (em []byte, err error) {
+	if len(m) == 0 {
+		return nil, ErrEmptyMessage
+	}
+
+	Ke, Km, err := deriveKeys(ctx, params, z, s1, tr)
+	if err != nil {
 		return
 	}
 
-	d := messageTag(params.Hash, Km, em, s2)
+	_, span := tracer.Start(ctx, "ecies.symEncrypt")
+	em, err = symEncrypt(rand, params, Ke, m)
+	endSpan(span, err)
+	if err != nil {
+		return
+	}
+	tr.record("IV", em[:params.BlockSize], false)
+	tr.record("body", em[params.BlockSize:], false)
 
-	Rb := elliptic.Marshal(pub.Curve, R.PublicKey.X, R.PublicKey.Y)
-	ct := make([]byte, len(Rb)+len(em)+len(d))
-	copy(ct, Rb)
-	copy(ct[len(Rb):], em)
-	copy(ct[len(Rb)+len(em):], d)
-	ctBase64 = base64.StdEncoding.EncodeToString(ct)
+	d := messageTag(params.Hash, Km, em, s2)
+	tr.record("tag", d, false)
+	em = append(em, d...)
 	return
 }
 
-// Decrypt decrypts an ECIES ciphertext.
-func (prv *PrivateKey) Decrypt(rand io.Reader, ct string, s1, s2 []byte) (m []byte, err error) {
-	c, err := base64.StdEncoding.DecodeString(ct)
-	if c == nil || len(c) == 0 || err != nil {
+// openMessage checks the tag of a sealed message and decrypts it.
+func openMessage(ctx context.Context, rand io.Reader, params *ECIESParams, z, em, s1, s2 []byte, tr *Trace) (m []byte, err error) {
+	mEnd := len(em) - params.Hash().Size()
+	if mEnd <= params.BlockSize {
 		err = ErrInvalidMessage
 		return
 	}
-	params := prv.PublicKey.Params
-	if params == nil {
-		if params = ParamsFromCurve(prv.PublicKey.Curve); params == nil {
-			err = ErrUnsupportedECIESParameters
-			return
-		}
+	tr.record("IV", em[:params.BlockSize], false)
+	tr.record("body", em[params.BlockSize:mEnd], false)
+	tr.record("tag", em[mEnd:], false)
+
+	Ke, Km, err := deriveKeys(ctx, params, z, s1, tr)
+	if err != nil {
+		return
+	}
+
+	d := messageTag(params.Hash, Km, em[:mEnd], s2)
+	tr.record("expected tag", d, false)
+	if subtle.ConstantTimeCompare(em[mEnd:], d) != 1 {
+		err = ErrInvalidMessage
+		return
 	}
-	curHash := params.Hash()
 
-	var (
-		rLen   int
-		hLen   int = curHash.Size()
-		mStart int
-		mEnd   int
-	)
+	_, span := tracer.Start(ctx, "ecies.symDecrypt")
+	m, err = symDecrypt(rand, params, Ke, em[:mEnd])
+	endSpan(span, err)
+	if err == nil {
+		tr.record("plaintext", m, true)
+	}
+	return
+}
 
+// parseCiphertext splits an ECIES ciphertext into the ephemeral public key and
+// the sealed message.
+func parseCiphertext(curve elliptic.Curve, params *ECIESParams, c []byte) (R *PublicKey, em []byte, err error) {
 	switch c[0] {
 	case 2, 3, 4:
-		rLen = (prv.PublicKey.Curve.Params().BitSize + 7) / 4
-		if len(c) < (rLen + hLen + 1) {
-			err = ErrInvalidMessage
-			return
-		}
 	default:
 		err = ErrInvalidPublicKey
 		return
 	}
 
-	mStart = rLen
-	mEnd = len(c) - hLen
+	rLen := 2*((curve.Params().BitSize+7)/8) + 1
+	if len(c) < (rLen + params.Hash().Size() + 1) {
+		err = ErrInvalidMessage
+		return
+	}
 
-	R := new(PublicKey)
-	R.Curve = prv.PublicKey.Curve
+	R = new(PublicKey)
+	R.Curve = curve
 	R.X, R.Y = elliptic.Unmarshal(R.Curve, c[:rLen])
 	if R.X == nil {
 		err = ErrInvalidPublicKey
 		return
 	}
+	em = c[rLen:]
+	return
+}
 
-	z, err := prv.GenerateShared(R, params.KeyLen, params.MacLen)
+// paramsOf returns the parameters of a key, falling back to the defaults of
+// its curve.
+func paramsOf(pub *PublicKey) *ECIESParams {
+	if pub.Params != nil {
+		return pub.Params
+	}
+	return ParamsFromCurve(pub.Curve)
+}
+
+// Encrypt encrypts m for the public key with ECIES and returns the
+// ciphertext encoded with base64.
+func Encrypt(rand io.Reader, pub *PublicKey, m, s1, s2 []byte) (ctBase64 string, err error) {
+	return EncryptContext(context.Background(), rand, pub, m, s1, s2)
+}
+
+// EncryptContext works like Encrypt and records tracing spans under ctx.
+func EncryptContext(ctx context.Context, rand io.Reader, pub *PublicKey, m, s1, s2 []byte) (ctBase64 string, err error) {
+	ct, err := encrypt(ctx, rand, pub, m, s1, s2, nil)
+	return encodeCiphertext(ct), err
+}
+
+// EncryptTrace works like Encrypt and also records every intermediate value.
+func EncryptTrace(rand io.Reader, pub *PublicKey, m, s1, s2 []byte) (ctBase64 string, tr *Trace, err error) {
+	tr = new(Trace)
+	ct, err := encrypt(context.Background(), rand, pub, m, s1, s2, tr)
+	return encodeCiphertext(ct), tr, err
+}
+
+// EncryptRaw works like EncryptContext but returns the binary ciphertext
+// without the base64 encoding.
+func EncryptRaw(ctx context.Context, rand io.Reader, pub *PublicKey, m, s1, s2 []byte) (ct []byte, err error) {
+	return encrypt(ctx, rand, pub, m, s1, s2, nil)
+}
+
+func encodeCiphertext(ct []byte) string {
+	if len(ct) == 0 {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(ct)
+}
+
+func encrypt(ctx context.Context, rand io.Reader, pub *PublicKey, m, s1, s2 []byte, tr *Trace) (ct []byte, err error) {
+	params := paramsOf(pub)
+	if params == nil {
+		err = ErrUnsupportedECIESParameters
+		return
+	}
+	R, err := GenerateKey(rand, pub.Curve, params)
 	if err != nil {
 		return
 	}
+	Rb := elliptic.Marshal(pub.Curve, R.PublicKey.X, R.PublicKey.Y)
+	tr.record("r", R.D.Bytes(), true)
+	tr.record("R", Rb, false)
 
-	K, err := concatKDF(curHash, z, s1, params.KeyLen+params.MacLen)
+	_, span := tracer.Start(ctx, "ecies.GenerateShared")
+	z, err := R.GenerateShared(pub, params.KeyLen, params.MacLen)
+	endSpan(span, err)
 	if err != nil {
 		return
 	}
+	tr.record("z", z, true)
 
-	Ke := K[:params.KeyLen]
-	Km := K[params.MacLen:]
-	curHash.Write(Km)
-	Km = curHash.Sum(nil)
-	curHash.Reset()
+	em, err := sealMessage(ctx, rand, params, z, m, s1, s2, tr)
+	if err != nil {
+		return
+	}
 
-	d := messageTag(params.Hash, Km, c[mStart:mEnd], s2)
-	if subtle.ConstantTimeCompare(c[mEnd:], d) != 1 {
-		err = ErrInvalidMessage
+	ct = make([]byte, len(Rb)+len(em))
+	copy(ct, Rb)
+	copy(ct[len(Rb):], em)
+	return
+}
+
+// Decrypt decrypts an ECIES ciphertext, including ciphertexts re-encrypted
+// for this key by a proxy.
+func (prv *PrivateKey) Decrypt(rand io.Reader, ct string, s1, s2 []byte) (m []byte, err error) {
+	return prv.DecryptContext(context.Background(), rand, ct, s1, s2)
+}
+
+// DecryptContext works like Decrypt and records tracing spans under ctx.
+func (prv *PrivateKey) DecryptContext(ctx context.Context, rand io.Reader, ct string, s1, s2 []byte) (m []byte, err error) {
+	c, err := decodeCiphertext(ct)
+	if err != nil {
 		return
 	}
+	return prv.decrypt(ctx, rand, c, s1, s2, nil)
+}
 
-	m, err = symDecrypt(rand, params, Ke, c[mStart:mEnd])
+// DecryptTrace works like Decrypt and also records every intermediate value.
+func (prv *PrivateKey) DecryptTrace(rand io.Reader, ct string, s1, s2 []byte) (m []byte, tr *Trace, err error) {
+	tr = new(Trace)
+	c, err := decodeCiphertext(ct)
+	if err != nil {
+		return
+	}
+	m, err = prv.decrypt(context.Background(), rand, c, s1, s2, tr)
 	return
 }
+
+// DecryptRaw works like DecryptContext on a binary ciphertext, as returned
+// by EncryptRaw.
+func (prv *PrivateKey) DecryptRaw(ctx context.Context, rand io.Reader, ct, s1, s2 []byte) (m []byte, err error) {
+	if len(ct) == 0 {
+		return nil, ErrInvalidMessage
+	}
+	return prv.decrypt(ctx, rand, ct, s1, s2, nil)
+}
+
+func decodeCiphertext(ct string) ([]byte, error) {
+	c, err := base64.StdEncoding.DecodeString(ct)
+	if c == nil || len(c) == 0 || err != nil {
+		return nil, ErrInvalidMessage
+	}
+	return c, nil
+}
+
+func (prv *PrivateKey) decrypt(ctx context.Context, rand io.Reader, c, s1, s2 []byte, tr *Trace) (m []byte, err error) {
+	params := paramsOf(&prv.PublicKey)
+	if params == nil {
+		err = ErrUnsupportedECIESParameters
+		return
+	}
+
+	if c[0] == versionReencrypted {
+		return prv.decryptReencrypted(ctx, rand, params, c[1:], s1, s2, tr)
+	}
+
+	R, em, err := parseCiphertext(prv.PublicKey.Curve, params, c)
+	if err != nil {
+		return
+	}
+	tr.record("R", elliptic.Marshal(R.Curve, R.X, R.Y), false)
+
+	_, span := tracer.Start(ctx, "ecies.GenerateShared")
+	z, err := prv.GenerateShared(R, params.KeyLen, params.MacLen)
+	endSpan(span, err)
+	if err != nil {
+		return
+	}
+	tr.record("z", z, true)
+
+	return openMessage(ctx, rand, params, z, em, s1, s2, tr)
+}
@@ -0,0 +1,42 @@
+package cypher
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestEncryptShortMessages(t *testing.T) {
+	prv := generateTestKey(t)
+	for _, m := range [][]byte{{0}, []byte("x"), bytes.Repeat([]byte("message"), 10)} {
+		ct, err := Encrypt(rand.Reader, &prv.PublicKey, m, nil, nil)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", len(m), err)
+		}
+		got, err := prv.Decrypt(rand.Reader, ct, nil, nil)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", len(m), err)
+		}
+		if !bytes.Equal(got, m) {
+			t.Errorf("%d bytes: got %x", len(m), got)
+		}
+	}
+}
+
+func TestEncryptEmpty(t *testing.T) {
+	prv := generateTestKey(t)
+	for _, m := range [][]byte{nil, {}} {
+		if ct, err := Encrypt(rand.Reader, &prv.PublicKey, m, nil, nil); !errors.Is(err, ErrEmptyMessage) {
+			t.Errorf("Encrypt: got %q, %v, want %v", ct, err, ErrEmptyMessage)
+		}
+	}
+
+	hybrid, err := GenerateHybridKey(rand.Reader, DefaultCurve, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct, err := EncryptHybrid(rand.Reader, hybrid.Public(), nil, nil, nil); !errors.Is(err, ErrEmptyMessage) {
+		t.Errorf("EncryptHybrid: got %q, %v, want %v", ct, err, ErrEmptyMessage)
+	}
+}
@@ -0,0 +1,167 @@
+package cypher
+
+import (
+	"crypto/elliptic"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+var (
+	ErrMessageEncoding = fmt.Errorf("ecies: message can't be encoded as a curve point")
+)
+
+// koblitzK is the number of x-coordinates tried per message chunk in Koblitz
+// encoding. The chance that none of them lies on the curve is about 2^-256.
+const koblitzK = 256
+
+var three = big.NewInt(3)
+
+// curveRHS computes x^3 - 3x + b mod p for the NIST curves.
+func curveRHS(params *elliptic.CurveParams, x *big.Int) *big.Int {
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+
+	threeX := new(big.Int).Mul(x, three)
+	x3.Sub(x3, threeX)
+	x3.Add(x3, params.B)
+	return x3.Mod(x3, params.P)
+}
+
+// checkCurveForm fails with ErrInvalidCurve unless the curve is
+// y^2 = x^3 - 3x + b. elliptic.CurveParams carries no a, and both the Koblitz
+// encoding and point compression assume a = -3, so the generator is checked
+// against that equation.
+func checkCurveForm(curve elliptic.Curve) error {
+	params := curve.Params()
+	y2 := new(big.Int).Mul(params.Gy, params.Gy)
+	y2.Mod(y2, params.P)
+	if y2.Cmp(curveRHS(params, params.Gx)) != 0 {
+		return ErrInvalidCurve
+	}
+	return nil
+}
+
+// chunkMessage splits m into chunks of size bytes. Each chunk is prefixed with
+// a 0x01 byte when turned into an integer, so leading zero bytes survive.
+func chunkMessage(m []byte, size int) []*big.Int {
+	var chunks []*big.Int
+	for len(m) > 0 {
+		n := min(size, len(m))
+		chunks = append(chunks, chunkToInt(m[:n]))
+		m = m[n:]
+	}
+	return chunks
+}
+
+func chunkToInt(chunk []byte) *big.Int {
+	return new(big.Int).SetBytes(append([]byte{1}, chunk...))
+}
+
+func intToChunk(v *big.Int) ([]byte, error) {
+	b := v.Bytes()
+	if len(b) == 0 || b[0] != 1 {
+		return nil, ErrInvalidMessage
+	}
+	return b[1:], nil
+}
+
+// koblitzChunkSize is the number of message bytes encoded in one point: the
+// prefixed chunk times koblitzK has to stay below the field prime.
+func koblitzChunkSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize+7)/8 - 3
+}
+
+// koblitzEncode maps m to the point with x = m·K + j for the first j < K that
+// lies on the curve (Koblitz, 1987).
+func koblitzEncode(curve elliptic.Curve, m *big.Int) (x, y *big.Int, err error) {
+	params := curve.Params()
+	base := new(big.Int).Mul(m, big.NewInt(koblitzK))
+	for j := int64(0); j < koblitzK; j++ {
+		x = new(big.Int).Add(base, big.NewInt(j))
+		if y = new(big.Int).ModSqrt(curveRHS(params, x), params.P); y != nil {
+			return x, y, nil
+		}
+	}
+	return nil, nil, ErrMessageEncoding
+}
+
+// koblitzDecode recovers m = floor(x / K).
+func koblitzDecode(x *big.Int) *big.Int {
+	return new(big.Int).Div(x, big.NewInt(koblitzK))
+}
+
+func negate(curve elliptic.Curve, x, y *big.Int) (*big.Int, *big.Int) {
+	ny := new(big.Int).Sub(curve.Params().P, y)
+	return x, ny.Mod(ny, curve.Params().P)
+}
+
+// EncryptElGamal encrypts m with EC-ElGamal. The message is cut into chunks,
+// each chunk is Koblitz-encoded as a point M and encrypted as the pair
+// (k·G, M + k·Q) for a fresh k. Points are stored compressed.
+//
+// Unlike ECIES the ciphertext is not authenticated; the scheme is provided
+// for comparison only. Curves with a != -3 fail with ErrInvalidCurve.
+func EncryptElGamal(rand io.Reader, pub *PublicKey, m []byte) (ctBase64 string, err error) {
+	curve := pub.Curve
+	if err = checkCurveForm(curve); err != nil {
+		return
+	}
+	ct := []byte{versionElGamal}
+	for _, chunk := range chunkMessage(m, koblitzChunkSize(curve)) {
+		mx, my, err := koblitzEncode(curve, chunk)
+		if err != nil {
+			return "", err
+		}
+
+		k, err := GenerateKey(rand, curve, pub.Params)
+		if err != nil {
+			return "", err
+		}
+		sx, sy := curve.ScalarMult(pub.X, pub.Y, k.D.Bytes())
+		cx, cy := curve.Add(mx, my, sx, sy)
+
+		ct = append(ct, elliptic.MarshalCompressed(curve, k.X, k.Y)...)
+		ct = append(ct, elliptic.MarshalCompressed(curve, cx, cy)...)
+	}
+	ctBase64 = base64.StdEncoding.EncodeToString(ct)
+	return
+}
+
+// DecryptElGamal decrypts an EC-ElGamal ciphertext by computing
+// M = C2 - d·C1 for every chunk.
+func (prv *PrivateKey) DecryptElGamal(ct string) (m []byte, err error) {
+	c, err := base64.StdEncoding.DecodeString(ct)
+	if len(c) == 0 || err != nil || c[0] != versionElGamal {
+		return nil, ErrInvalidMessage
+	}
+	curve := prv.PublicKey.Curve
+	if err = checkCurveForm(curve); err != nil {
+		return nil, err
+	}
+	pointLen := (curve.Params().BitSize+7)/8 + 1
+	c = c[1:]
+	if len(c)%(2*pointLen) != 0 {
+		return nil, ErrInvalidMessage
+	}
+
+	m = make([]byte, 0)
+	for ; len(c) > 0; c = c[2*pointLen:] {
+		x1, y1 := elliptic.UnmarshalCompressed(curve, c[:pointLen])
+		x2, y2 := elliptic.UnmarshalCompressed(curve, c[pointLen:2*pointLen])
+		if x1 == nil || x2 == nil {
+			return nil, ErrInvalidMessage
+		}
+
+		sx, sy := curve.ScalarMult(x1, y1, prv.D.Bytes())
+		sx, sy = negate(curve, sx, sy)
+		mx, _ := curve.Add(x2, y2, sx, sy)
+		chunk, err := intToChunk(koblitzDecode(mx))
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, chunk...)
+	}
+	return m, nil
+}
@@ -0,0 +1,83 @@
+package cypher
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func hexInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		t.Fatalf("bad constant %s", s)
+	}
+	return v
+}
+
+// secp256k1 is y^2 = x^3 + 7, a curve with a = 0. Only its parameters are
+// used: the arithmetic of elliptic.CurveParams assumes a = -3.
+func secp256k1(t *testing.T) elliptic.Curve {
+	t.Helper()
+	return &elliptic.CurveParams{
+		Name:    "secp256k1",
+		BitSize: 256,
+		P:       hexInt(t, "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"),
+		N:       hexInt(t, "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"),
+		B:       big.NewInt(7),
+		Gx:      hexInt(t, "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
+		Gy:      hexInt(t, "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"),
+	}
+}
+
+func TestSchemesRoundTrip(t *testing.T) {
+	m := bytes.Repeat([]byte("chunked message "), 8)
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		prv, err := GenerateKey(rand.Reader, curve, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ct, err := EncryptElGamal(rand.Reader, &prv.PublicKey, m)
+		if err != nil {
+			t.Fatalf("%s: EncryptElGamal: %v", curve.Params().Name, err)
+		}
+		if got, err := prv.DecryptElGamal(ct); err != nil || !bytes.Equal(got, m) {
+			t.Errorf("%s: DecryptElGamal: %q, %v", curve.Params().Name, got, err)
+		}
+
+		ct, err = EncryptMenezesVanstone(rand.Reader, &prv.PublicKey, m)
+		if err != nil {
+			t.Fatalf("%s: EncryptMenezesVanstone: %v", curve.Params().Name, err)
+		}
+		if got, err := prv.DecryptMenezesVanstone(ct); err != nil || !bytes.Equal(got, m) {
+			t.Errorf("%s: DecryptMenezesVanstone: %q, %v", curve.Params().Name, got, err)
+		}
+	}
+}
+
+func TestSchemesRejectCurveForm(t *testing.T) {
+	curve := secp256k1(t)
+	params := curve.Params()
+	prv := &PrivateKey{
+		PublicKey: PublicKey{X: params.Gx, Y: params.Gy, Curve: curve},
+		D:         big.NewInt(1),
+	}
+
+	if _, err := EncryptElGamal(rand.Reader, &prv.PublicKey, []byte("m")); !errors.Is(err, ErrInvalidCurve) {
+		t.Errorf("EncryptElGamal: got %v, want %v", err, ErrInvalidCurve)
+	}
+	if _, err := prv.DecryptElGamal(base64.StdEncoding.EncodeToString([]byte{versionElGamal})); !errors.Is(err, ErrInvalidCurve) {
+		t.Errorf("DecryptElGamal: got %v, want %v", err, ErrInvalidCurve)
+	}
+	if _, err := EncryptMenezesVanstone(rand.Reader, &prv.PublicKey, []byte("m")); !errors.Is(err, ErrInvalidCurve) {
+		t.Errorf("EncryptMenezesVanstone: got %v, want %v", err, ErrInvalidCurve)
+	}
+	if _, err := prv.DecryptMenezesVanstone(base64.StdEncoding.EncodeToString([]byte{versionMV})); !errors.Is(err, ErrInvalidCurve) {
+		t.Errorf("DecryptMenezesVanstone: got %v, want %v", err, ErrInvalidCurve)
+	}
+}
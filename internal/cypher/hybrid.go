@@ -0,0 +1,272 @@
+package cypher
+
+import (
+	"context"
+	"crypto/elliptic"
+	"crypto/mlkem"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrInvalidHybridKey = fmt.Errorf("ecies: invalid hybrid key")
+)
+
+// id-alg-ml-kem-768, see FIPS 203 and the NIST CSOR algorithm registry.
+var oidMLKEM768 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 2}
+
+// HybridPublicKey pairs an elliptic curve public key with an ML-KEM-768
+// encapsulation key. Messages encrypted to it stay confidential as long as
+// either ECDH or ML-KEM remains unbroken.
+type HybridPublicKey struct {
+	PublicKey
+	KEM *mlkem.EncapsulationKey768
+}
+
+// HybridPrivateKey is the private counterpart of HybridPublicKey.
+type HybridPrivateKey struct {
+	PrivateKey
+	KEM *mlkem.DecapsulationKey768
+}
+
+// GenerateHybridKey generates an elliptic curve keypair together with an
+// ML-KEM-768 keypair. If params is nil, the recommended default parameters
+// for the curve will be chosen.
+func GenerateHybridKey(rand io.Reader, curve elliptic.Curve, params *ECIESParams) (prv *HybridPrivateKey, err error) {
+	ec, err := GenerateKey(rand, curve, params)
+	if err != nil {
+		return
+	}
+	kem, err := mlkem.GenerateKey768()
+	if err != nil {
+		return
+	}
+	return &HybridPrivateKey{PrivateKey: *ec, KEM: kem}, nil
+}
+
+// Public returns the hybrid public key.
+func (prv *HybridPrivateKey) Public() *HybridPublicKey {
+	return &HybridPublicKey{PublicKey: prv.PrivateKey.PublicKey, KEM: prv.KEM.EncapsulationKey()}
+}
+
+// hybridShared combines the ECDH secret with the ML-KEM shared key. The KEM
+// ciphertext is bound into the KDF input as well.
+func hybridShared(z, kemShared, kemCiphertext []byte) []byte {
+	shared := make([]byte, 0, len(z)+len(kemShared)+len(kemCiphertext))
+	shared = append(shared, z...)
+	shared = append(shared, kemShared...)
+	return append(shared, kemCiphertext...)
+}
+
+// EncryptHybrid encrypts m like Encrypt, but with the KDF keyed by both the
+// ECDH secret and an ML-KEM-768 encapsulation. The ciphertext starts with its
+// own version byte, followed by the ephemeral point, the KEM ciphertext and
+// the sealed message.
+func EncryptHybrid(rand io.Reader, pub *HybridPublicKey, m, s1, s2 []byte) (ctBase64 string, err error) {
+	params := paramsOf(&pub.PublicKey)
+	if params == nil {
+		err = ErrUnsupportedECIESParameters
+		return
+	}
+	R, err := GenerateKey(rand, pub.Curve, params)
+	if err != nil {
+		return
+	}
+
+	z, err := R.GenerateShared(&pub.PublicKey, params.KeyLen, params.MacLen)
+	if err != nil {
+		return
+	}
+	kemShared, kemCiphertext := pub.KEM.Encapsulate()
+
+	em, err := sealMessage(context.Background(), rand, params, hybridShared(z, kemShared, kemCiphertext), m, s1, s2, nil)
+	if err != nil {
+		return
+	}
+
+	Rb := elliptic.Marshal(pub.Curve, R.PublicKey.X, R.PublicKey.Y)
+	ct := make([]byte, 0, 1+len(Rb)+len(kemCiphertext)+len(em))
+	ct = append(ct, versionHybrid)
+	ct = append(ct, Rb...)
+	ct = append(ct, kemCiphertext...)
+	ct = append(ct, em...)
+	ctBase64 = base64.StdEncoding.EncodeToString(ct)
+	return
+}
+
+// Decrypt decrypts a hybrid ciphertext. Plain ECIES ciphertexts for the
+// elliptic curve half of the key are accepted as well.
+func (prv *HybridPrivateKey) Decrypt(rand io.Reader, ct string, s1, s2 []byte) (m []byte, err error) {
+	c, err := base64.StdEncoding.DecodeString(ct)
+	if c == nil || len(c) == 0 || err != nil {
+		err = ErrInvalidMessage
+		return
+	}
+	if c[0] != versionHybrid {
+		return prv.PrivateKey.Decrypt(rand, ct, s1, s2)
+	}
+	params := paramsOf(&prv.PublicKey)
+	if params == nil {
+		err = ErrUnsupportedECIESParameters
+		return
+	}
+
+	curve := prv.PublicKey.Curve
+	rLen := 2*((curve.Params().BitSize+7)/8) + 1
+	c = c[1:]
+	if len(c) < rLen+mlkem.CiphertextSize768 {
+		err = ErrInvalidMessage
+		return
+	}
+	kemCiphertext := c[rLen : rLen+mlkem.CiphertextSize768]
+
+	// Reassemble a plain ECIES ciphertext around the KEM ciphertext.
+	plain := make([]byte, 0, len(c)-mlkem.CiphertextSize768)
+	plain = append(plain, c[:rLen]...)
+	plain = append(plain, c[rLen+mlkem.CiphertextSize768:]...)
+	R, em, err := parseCiphertext(curve, params, plain)
+	if err != nil {
+		return
+	}
+
+	z, err := prv.GenerateShared(R, params.KeyLen, params.MacLen)
+	if err != nil {
+		return
+	}
+	kemShared, err := prv.KEM.Decapsulate(kemCiphertext)
+	if err != nil {
+		err = ErrInvalidMessage
+		return
+	}
+
+	return openMessage(context.Background(), rand, params, hybridShared(z, kemShared, kemCiphertext), em, s1, s2, nil)
+}
+
+type asnHybridPublicKey struct {
+	Algorithm asn1.ObjectIdentifier
+	EC        asn1.BitString
+	KEM       asn1.BitString
+}
+
+type asnHybridPrivateKey struct {
+	Version   asnECPrivKeyVer
+	Algorithm asn1.ObjectIdentifier
+	EC        []byte
+	KEM       []byte
+}
+
+// MarshalHybridPublic Encode a hybrid public key to DER format.
+func MarshalHybridPublic(pub *HybridPublicKey) ([]byte, error) {
+	ec, err := MarshalPublic(&pub.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	kem := pub.KEM.Bytes()
+	return asn1.Marshal(asnHybridPublicKey{
+		Algorithm: oidMLKEM768,
+		EC:        asn1.BitString{BitLength: len(ec) * 8, Bytes: ec},
+		KEM:       asn1.BitString{BitLength: len(kem) * 8, Bytes: kem},
+	})
+}
+
+// UnmarshalHybridPublic Decode a DER-encoded hybrid public key.
+func UnmarshalHybridPublic(in []byte) (pub *HybridPublicKey, err error) {
+	var asnPub asnHybridPublicKey
+
+	if _, err = asn1.Unmarshal(in, &asnPub); err != nil {
+		return
+	} else if !asnPub.Algorithm.Equal(oidMLKEM768) {
+		err = ErrInvalidHybridKey
+		return
+	}
+
+	ec, err := UnmarshalPublic(asnPub.EC.Bytes)
+	if err != nil {
+		return
+	}
+	kem, err := mlkem.NewEncapsulationKey768(asnPub.KEM.Bytes)
+	if err != nil {
+		return nil, ErrInvalidHybridKey
+	}
+	return &HybridPublicKey{PublicKey: *ec, KEM: kem}, nil
+}
+
+// MarshalHybridPrivate Encode a hybrid private key to DER format. The ML-KEM
+// half is stored as its 64-byte seed.
+func MarshalHybridPrivate(prv *HybridPrivateKey) ([]byte, error) {
+	ec, err := MarshalPrivate(&prv.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(asnHybridPrivateKey{
+		Version:   asnECPrivKeyVer1,
+		Algorithm: oidMLKEM768,
+		EC:        ec,
+		KEM:       prv.KEM.Bytes(),
+	})
+}
+
+// UnmarshalHybridPrivate Decode a DER-encoded hybrid private key.
+func UnmarshalHybridPrivate(in []byte) (prv *HybridPrivateKey, err error) {
+	var asnPrv asnHybridPrivateKey
+
+	if _, err = asn1.Unmarshal(in, &asnPrv); err != nil {
+		return
+	} else if asnPrv.Version != asnECPrivKeyVer1 || !asnPrv.Algorithm.Equal(oidMLKEM768) {
+		err = ErrInvalidHybridKey
+		return
+	}
+
+	ec, err := UnmarshalPrivate(asnPrv.EC)
+	if err != nil {
+		return
+	}
+	kem, err := mlkem.NewDecapsulationKey768(asnPrv.KEM)
+	if err != nil {
+		return nil, ErrInvalidHybridKey
+	}
+	return &HybridPrivateKey{PrivateKey: *ec, KEM: kem}, nil
+}
+
+// ExportHybridPublicPEM Export a hybrid public key to PEM format.
+func ExportHybridPublicPEM(pub *HybridPublicKey) (out []byte, err error) {
+	der, err := MarshalHybridPublic(pub)
+	if err != nil {
+		return
+	}
+	return encodePEM("HYBRID ELLIPTIC CURVE ML-KEM-768 PUBLIC KEY", der)
+}
+
+// ExportHybridPrivatePEM Export a hybrid private key to PEM format.
+func ExportHybridPrivatePEM(prv *HybridPrivateKey) (out []byte, err error) {
+	der, err := MarshalHybridPrivate(prv)
+	if err != nil {
+		return
+	}
+	return encodePEM("HYBRID ELLIPTIC CURVE ML-KEM-768 PRIVATE KEY", der)
+}
+
+// ImportHybridPublicPEM Import a PEM-encoded hybrid public key.
+func ImportHybridPublicPEM(in []byte) (pub *HybridPublicKey, err error) {
+	p, _ := pem.Decode(in)
+	if p == nil || p.Type != "HYBRID ELLIPTIC CURVE ML-KEM-768 PUBLIC KEY" {
+		return nil, ErrInvalidHybridKey
+	}
+
+	pub, err = UnmarshalHybridPublic(p.Bytes)
+	return
+}
+
+// ImportHybridPrivatePEM Import a PEM-encoded hybrid private key.
+func ImportHybridPrivatePEM(in []byte) (prv *HybridPrivateKey, err error) {
+	p, _ := pem.Decode(in)
+	if p == nil || p.Type != "HYBRID ELLIPTIC CURVE ML-KEM-768 PRIVATE KEY" {
+		return nil, ErrInvalidHybridKey
+	}
+
+	prv, err = UnmarshalHybridPrivate(p.Bytes)
+	return
+}
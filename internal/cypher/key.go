@@ -66,17 +66,16 @@ func (prv *PrivateKey) GenerateShared(pub *PublicKey, skLen, macLen int) (sk []b
 		return nil, ErrSharedKeyIsPointAtInfinity
 	}
 
-	sk = make([]byte, skLen+macLen)
-	skBytes := x.Bytes()
-
-	//fmt.Println("Length of skBytes:", len(skBytes), "Total length of sk:", len(sk))
+	return sharedFromX(pub, x), nil
+}
 
-	//Check if skBytes can fit into sk
-	if len(skBytes) > len(sk) {
-		return nil, ErrSharedKeyTooBig
-	}
+// sharedFromX encodes the x-coordinate of a shared point as a field element,
+// so it always fits regardless of the requested key lengths.
+func sharedFromX(pub *PublicKey, x *big.Int) []byte {
+	sk := make([]byte, MaxSharedKeyLength(pub))
+	skBytes := x.Bytes()
 	copy(sk[len(sk)-len(skBytes):], skBytes)
-	return sk, nil
+	return sk
 }
 
 // ExportECDSA Export an ECIES private key as an ECDSA private key.
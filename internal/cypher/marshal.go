@@ -3,7 +3,6 @@ package cypher
 import (
 	"bytes"
 	"crypto"
-	"crypto/aes"
 	"crypto/elliptic"
 	"crypto/sha1"
 	"crypto/sha256"
@@ -30,94 +29,40 @@ func doScheme(base, v []int) asn1.ObjectIdentifier {
 	return append(oidInts, v...)
 }
 
-// curve OID code taken from crypto/x509, including
-//   - oidNameCurve*
-//   - namedCurveFromOID
-//   - oidFromNamedCurve
-//
-// RFC 5480, 2.1.1.1. Named Curve
-//
-//	secp224r1 OBJECT IDENTIFIER ::= {
-//	  iso(1) identified-organization(3) certicom(132) curve(0) 33 }
-//
-//	secp256r1 OBJECT IDENTIFIER ::= {
-//	  iso(1) member-body(2) us(840) ansi-X9-62(10045) curves(3)
-//	  prime(1) 7 }
-//
-//	secp384r1 OBJECT IDENTIFIER ::= {
-//	  iso(1) identified-organization(3) certicom(132) curve(0) 34 }
-//
-//	secp521r1 OBJECT IDENTIFIER ::= {
-//	  iso(1) identified-organization(3) certicom(132) curve(0) 35 }
-//
-// NB: secp256r1 is equivalent to prime256v1
+// secgNamedCurve is the OID of a named curve (RFC 5480, 2.1.1.1). The known
+// curves and their OIDs live in the registry.
 type secgNamedCurve asn1.ObjectIdentifier
 
-var (
-	secgNamedCurveP224 = secgNamedCurve{1, 3, 132, 0, 33}
-	secgNamedCurveP256 = secgNamedCurve{1, 2, 840, 10045, 3, 1, 7}
-	secgNamedCurveP384 = secgNamedCurve{1, 3, 132, 0, 34}
-	secgNamedCurveP521 = secgNamedCurve{1, 3, 132, 0, 35}
-	rawCurveP224       = []byte{6, 5, 4, 3, 1, 2, 9, 4, 0, 3, 3}
-	rawCurveP256       = []byte{6, 8, 4, 2, 1, 3, 4, 7, 2, 2, 0, 6, 6, 1, 3, 1, 7}
-	rawCurveP384       = []byte{6, 5, 4, 3, 1, 2, 9, 4, 0, 3, 4}
-	rawCurveP521       = []byte{6, 5, 4, 3, 1, 2, 9, 4, 0, 3, 5}
-)
-
 func rawCurve(curve elliptic.Curve) []byte {
-	switch curve {
-	case elliptic.P224():
-		return rawCurveP224
-	case elliptic.P256():
-		return rawCurveP256
-	case elliptic.P384():
-		return rawCurveP384
-	case elliptic.P521():
-		return rawCurveP521
-	default:
+	oid, ok := oidFromNamedCurve(curve)
+	if !ok {
 		return nil
 	}
+	raw, err := asn1.Marshal(asn1.ObjectIdentifier(oid))
+	if err != nil {
+		return nil
+	}
+	return raw
 }
 
 func (curve secgNamedCurve) Equal(curve2 secgNamedCurve) bool {
-	if len(curve) != len(curve2) {
-		return false
-	}
-	for i, _ := range curve {
-		if curve[i] != curve2[i] {
-			return false
-		}
-	}
-	return true
+	return asn1.ObjectIdentifier(curve).Equal(asn1.ObjectIdentifier(curve2))
 }
 
 func namedCurveFromOID(curve secgNamedCurve) elliptic.Curve {
-	switch {
-	case curve.Equal(secgNamedCurveP224):
-		return elliptic.P224()
-	case curve.Equal(secgNamedCurveP256):
-		return elliptic.P256()
-	case curve.Equal(secgNamedCurveP384):
-		return elliptic.P384()
-	case curve.Equal(secgNamedCurveP521):
-		return elliptic.P521()
+	c, ok := curveByOID(asn1.ObjectIdentifier(curve))
+	if !ok {
+		return nil
 	}
-	return nil
+	return c.Curve
 }
 
 func oidFromNamedCurve(curve elliptic.Curve) (secgNamedCurve, bool) {
-	switch curve {
-	case elliptic.P224():
-		return secgNamedCurveP224, true
-	case elliptic.P256():
-		return secgNamedCurveP256, true
-	case elliptic.P384():
-		return secgNamedCurveP384, true
-	case elliptic.P521():
-		return secgNamedCurveP521, true
+	c, ok := LookupCurve(curve)
+	if !ok {
+		return nil, false
 	}
-
-	return nil, false
+	return secgNamedCurve(c.OID), true
 }
 
 // asnAlgorithmIdentifier represents the ASN.1 structure of the same name. See RFC
@@ -220,17 +165,15 @@ var idEcPublicKey = doScheme(idPublicKeyType, []int{1})
 var idEcPublicKeySupplemented = doScheme(idPublicKeyType, []int{0})
 
 func curveToRaw(curve elliptic.Curve) (rv asn1.RawValue, ok bool) {
-	switch curve {
-	case elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521():
-		raw := rawCurve(curve)
-		return asn1.RawValue{
-			Tag:       30,
-			Bytes:     raw[2:],
-			FullBytes: raw,
-		}, true
-	default:
+	raw := rawCurve(curve)
+	if raw == nil {
 		return rv, false
 	}
+	return asn1.RawValue{
+		Tag:       30,
+		Bytes:     raw[2:],
+		FullBytes: raw,
+	}, true
 }
 
 func asnECPublicKeyType(curve elliptic.Curve) (algo asnAlgorithmIdentifier, ok bool) {
@@ -374,87 +317,39 @@ type eccAlgorithmSet struct {
 
 // ASN.1 encode the ECIES parameters relevant to the encryption operations.
 func paramsToASNECIES(params *ECIESParams) (asnParams asnECIESParameters) {
-	if nil == params {
+	suite, ok := LookupSuite(params)
+	if !ok {
 		return
 	}
 	asnParams.KDF = asnNISTConcatenationKDF
 	asnParams.MAC = hmacFull
-	switch params.KeyLen {
-	case 16:
-		asnParams.Sym = aes128CTRinECIES
-	case 24:
-		asnParams.Sym = aes192CTRinECIES
-	case 32:
-		asnParams.Sym = aes256CTRinECIES
-	}
+	asnParams.Sym = asnSymmetricEncryption{Algorithm: suite.Sym}
 	return
 }
 
 // ASN.1 encode the ECIES parameters relevant to ECDH.
 func paramsToASNECDH(params *ECIESParams) (algo asnECDHAlgorithm) {
-	switch params.hashAlgo {
-	case crypto.SHA224:
-		algo = dhsinglepassStddhSha224kdf
-	case crypto.SHA256:
-		algo = dhsinglepassStddhSha256kdf
-	case crypto.SHA384:
-		algo = dhsinglepassStddhSha384kdf
-	case crypto.SHA512:
-		algo = dhsinglepassStddhSha512kdf
-	default:
+	suite, ok := LookupSuite(params)
+	if !ok {
+		return
 	}
-	return
+	return asnECDHAlgorithm{Algorithm: suite.ECDH}
 }
 
-// ASN.1 decode the ECIES parameters relevant to the encryption stage.
-func asnECIEStoParams(asnParams asnECIESParameters, params *ECIESParams) {
-	if !asnParams.KDF.Cmp(asnNISTConcatenationKDF) {
-		params = nil
-		return
-	} else if !asnParams.MAC.Cmp(hmacFull) {
-		params = nil
-		return
+// ASN.1 decode the ECIES parameters of a public key, returning nil if they do
+// not describe a registered suite.
+func asnToParams(algos eccAlgorithmSet) *ECIESParams {
+	if !algos.ECIES.KDF.Cmp(asnNISTConcatenationKDF) {
+		return nil
+	} else if !algos.ECIES.MAC.Cmp(hmacFull) {
+		return nil
 	}
 
-	switch {
-	case asnParams.Sym.Cmp(aes128CTRinECIES):
-		params.KeyLen = 16
-		params.BlockSize = 16
-		params.Cipher = aes.NewCipher
-	case asnParams.Sym.Cmp(aes192CTRinECIES):
-		params.KeyLen = 24
-		params.BlockSize = 16
-		params.Cipher = aes.NewCipher
-	case asnParams.Sym.Cmp(aes256CTRinECIES):
-		params.KeyLen = 32
-		params.BlockSize = 16
-		params.Cipher = aes.NewCipher
-	default:
-		params = nil
-	}
-}
-
-// ASN.1 decode the ECIES parameters relevant to ECDH.
-func asnECDHtoParams(asnParams asnECDHAlgorithm, params *ECIESParams) {
-	if asnParams.Cmp(dhsinglepassStddhSha224kdf) {
-		params.hashAlgo = crypto.SHA224
-		params.Hash = sha256.New224
-		params.MacLen = 16
-	} else if asnParams.Cmp(dhsinglepassStddhSha256kdf) {
-		params.hashAlgo = crypto.SHA256
-		params.Hash = sha256.New
-		params.MacLen = 16
-	} else if asnParams.Cmp(dhsinglepassStddhSha384kdf) {
-		params.hashAlgo = crypto.SHA384
-		params.Hash = sha512.New384
-		params.MacLen = 16
-	} else if asnParams.Cmp(dhsinglepassStddhSha512kdf) {
-		params.hashAlgo = crypto.SHA512
-		params.Hash = sha512.New
-		params.MacLen = 32
-	} else {
-		params = nil
+	suite, ok := suiteByOIDs(algos.ECDH.Algorithm, algos.ECIES.Sym.Algorithm)
+	if !ok {
+		return nil
 	}
+	return suite.Params
 }
 
 func marshalSubjectPublicKeyInfo(pub *PublicKey) (subj asnSubjectPublicKeyInfo, err error) {
@@ -499,6 +394,10 @@ func UnmarshalPublic(in []byte) (pub *PublicKey, err error) {
 	}
 	pub = new(PublicKey)
 	pub.Curve = namedCurveFromOID(subj.Supplements.ECDomain)
+	if pub.Curve == nil {
+		err = ErrInvalidPublicKey
+		return
+	}
 	x, y := elliptic.Unmarshal(pub.Curve, subj.PublicKey.Bytes)
 	if x == nil {
 		err = ErrInvalidPublicKey
@@ -506,9 +405,7 @@ func UnmarshalPublic(in []byte) (pub *PublicKey, err error) {
 	}
 	pub.X = x
 	pub.Y = y
-	pub.Params = new(ECIESParams)
-	asnECIEStoParams(subj.Supplements.ECCAlgorithms.ECIES, pub.Params)
-	asnECDHtoParams(subj.Supplements.ECCAlgorithms.ECDH, pub.Params)
+	pub.Params = asnToParams(subj.Supplements.ECCAlgorithms)
 	if pub.Params == nil {
 		if pub.Params = ParamsFromCurve(pub.Curve); pub.Params == nil {
 			err = ErrInvalidPublicKey
@@ -0,0 +1,106 @@
+package cypher
+
+import (
+	"crypto/elliptic"
+	"encoding/base64"
+	"io"
+	"math/big"
+)
+
+// mvChunkSize is the number of message bytes carried by one field element in
+// the Menezes–Vanstone scheme; the prefixed chunk has to stay below p.
+func mvChunkSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize+7)/8 - 2
+}
+
+// EncryptMenezesVanstone encrypts m with the Menezes–Vanstone cryptosystem.
+// The message is not mapped onto the curve: it is cut into pairs of field
+// elements (m1, m2), and each pair is masked by the coordinates of the shared
+// point (c1, c2) = k·Q as (k·G, c1·m1 mod p, c2·m2 mod p).
+//
+// The scheme is malleable and leaks under known plaintext; it is provided for
+// comparison only. Ephemeral points are compressed, which assumes a = -3, so
+// other curves fail with ErrInvalidCurve.
+func EncryptMenezesVanstone(rand io.Reader, pub *PublicKey, m []byte) (ctBase64 string, err error) {
+	curve := pub.Curve
+	if err = checkCurveForm(curve); err != nil {
+		return
+	}
+	p := curve.Params().P
+	byteLen := (curve.Params().BitSize + 7) / 8
+
+	chunks := chunkMessage(m, mvChunkSize(curve))
+	if len(chunks)%2 == 1 {
+		chunks = append(chunks, chunkToInt(nil))
+	}
+
+	ct := []byte{versionMV}
+	for i := 0; i < len(chunks); i += 2 {
+		var k *PrivateKey
+		var c1, c2 *big.Int
+		for {
+			if k, err = GenerateKey(rand, curve, pub.Params); err != nil {
+				return
+			}
+			c1, c2 = curve.ScalarMult(pub.X, pub.Y, k.D.Bytes())
+			if c1.Sign() != 0 && c2.Sign() != 0 {
+				break
+			}
+		}
+
+		y1 := new(big.Int).Mul(c1, chunks[i])
+		y1.Mod(y1, p)
+		y2 := new(big.Int).Mul(c2, chunks[i+1])
+		y2.Mod(y2, p)
+
+		ct = append(ct, elliptic.MarshalCompressed(curve, k.X, k.Y)...)
+		ct = append(ct, y1.FillBytes(make([]byte, byteLen))...)
+		ct = append(ct, y2.FillBytes(make([]byte, byteLen))...)
+	}
+	ctBase64 = base64.StdEncoding.EncodeToString(ct)
+	return
+}
+
+// DecryptMenezesVanstone decrypts a Menezes–Vanstone ciphertext by computing
+// (c1, c2) = d·C0 and dividing the masks out.
+func (prv *PrivateKey) DecryptMenezesVanstone(ct string) (m []byte, err error) {
+	c, err := base64.StdEncoding.DecodeString(ct)
+	if len(c) == 0 || err != nil || c[0] != versionMV {
+		return nil, ErrInvalidMessage
+	}
+	curve := prv.PublicKey.Curve
+	if err = checkCurveForm(curve); err != nil {
+		return nil, err
+	}
+	p := curve.Params().P
+	byteLen := (curve.Params().BitSize + 7) / 8
+	blockLen := 3*byteLen + 1
+	c = c[1:]
+	if len(c)%blockLen != 0 {
+		return nil, ErrInvalidMessage
+	}
+
+	m = make([]byte, 0)
+	for ; len(c) > 0; c = c[blockLen:] {
+		x0, y0 := elliptic.UnmarshalCompressed(curve, c[:byteLen+1])
+		if x0 == nil {
+			return nil, ErrInvalidMessage
+		}
+		c1, c2 := curve.ScalarMult(x0, y0, prv.D.Bytes())
+		if c1.Sign() == 0 || c2.Sign() == 0 {
+			return nil, ErrInvalidMessage
+		}
+
+		y1 := new(big.Int).SetBytes(c[byteLen+1 : 2*byteLen+1])
+		y2 := new(big.Int).SetBytes(c[2*byteLen+1 : blockLen])
+		for _, pair := range [][2]*big.Int{{y1, c1}, {y2, c2}} {
+			v := new(big.Int).Mul(pair[0], new(big.Int).ModInverse(pair[1], p))
+			chunk, err := intToChunk(v.Mod(v, p))
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, chunk...)
+		}
+	}
+	return m, nil
+}
@@ -55,26 +55,18 @@ var (
 	}
 )
 
-var paramsFromCurve = map[elliptic.Curve]*ECIESParams{
-	elliptic.P256(): EciesAes128Sha256,
-	elliptic.P384(): EciesAes256Sha384,
-	elliptic.P521(): EciesAes256Sha512,
-}
-
-// ParamsFromCurve selects parameters optimal for the selected elliptic curve.
-// Only the curves P256, P384, and P512 are supported.
+// ParamsFromCurve selects parameters optimal for the selected elliptic curve,
+// that is the default suite it was registered with.
 func ParamsFromCurve(curve elliptic.Curve) (params *ECIESParams) {
-	//return paramsFromCurve[curve]
-	switch curve {
-	case elliptic.P256():
-		return EciesAes128Sha256
-	case elliptic.P384():
-		return EciesAes256Sha384
-	case elliptic.P521():
-		return EciesAes256Sha512
-	default:
+	c, ok := LookupCurve(curve)
+	if !ok || c.DefaultSuite == "" {
+		return nil
+	}
+	suite, ok := SuiteByName(c.DefaultSuite)
+	if !ok {
 		return nil
 	}
+	return suite.Params
 }
 
 // DefaultCurve The default curve for this package is the NIST P256 curve, which
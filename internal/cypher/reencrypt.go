@@ -0,0 +1,258 @@
+package cypher
+
+import (
+	"bytes"
+	"context"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+var (
+	ErrInvalidReencryptionKey = fmt.Errorf("ecies: invalid re-encryption key")
+	ErrAlreadyReencrypted     = fmt.Errorf("ecies: ciphertext is already re-encrypted")
+)
+
+// ReencryptionKey lets a proxy turn ciphertexts encrypted to the delegator
+// into ciphertexts for the delegatee without learning the plaintext.
+//
+// The scheme is non-interactive: the delegator only needs the delegatee's
+// public key. The delegator draws an ephemeral key x, derives d = H(X, B, x·B)
+// for the delegatee public key B and publishes rk = a/d along with X = x·G.
+// The proxy maps the ephemeral point R of a ciphertext to rk·R; the delegatee
+// recomputes d from b·X and recovers d·rk·R = a·R, the shared point of the
+// original ciphertext, so the KDF, MAC and symmetric steps are unchanged.
+//
+// The scheme is not collusion-safe: anyone holding rk and the delegatee
+// private key computes a = rk·d. Handing rk to a proxy is only safe if the
+// proxy and the delegatee never share what they know, so rk has to be
+// guarded like the delegator key itself.
+type ReencryptionKey struct {
+	RK        *big.Int
+	X         *big.Int
+	Y         *big.Int
+	Delegatee *PublicKey
+}
+
+// reencryptionFactor computes d = H(X, B, S) mod N, where X is the ephemeral
+// point of the re-encryption key, B the delegatee public key and S the
+// Diffie-Hellman point between them.
+func reencryptionFactor(params *ECIESParams, delegatee *PublicKey, x, y, sx, sy *big.Int) (*big.Int, error) {
+	curve := delegatee.Curve
+	hash := params.Hash()
+	hash.Write(elliptic.Marshal(curve, x, y))
+	hash.Write(elliptic.Marshal(curve, delegatee.X, delegatee.Y))
+	hash.Write(elliptic.Marshal(curve, sx, sy))
+
+	d := new(big.Int).SetBytes(hash.Sum(nil))
+	d.Mod(d, curve.Params().N)
+	if d.Sign() == 0 {
+		return nil, ErrInvalidReencryptionKey
+	}
+	return d, nil
+}
+
+// GenerateReencryptionKey creates a key delegating decryption rights of prv to
+// the holder of the delegatee private key. Both keys must use the same curve
+// and ECIES parameters.
+func (prv *PrivateKey) GenerateReencryptionKey(rand io.Reader, delegatee *PublicKey) (rk *ReencryptionKey, err error) {
+	if prv.PublicKey.Curve != delegatee.Curve {
+		return nil, ErrInvalidCurve
+	}
+	params := paramsOf(&prv.PublicKey)
+	delegateeParams := paramsOf(delegatee)
+	if params == nil || delegateeParams == nil || !sameParams(params, delegateeParams) {
+		return nil, ErrInvalidParams
+	}
+
+	for {
+		ephemeral, err := GenerateKey(rand, delegatee.Curve, params)
+		if err != nil {
+			return nil, err
+		}
+		sx, sy := delegatee.Curve.ScalarMult(delegatee.X, delegatee.Y, ephemeral.D.Bytes())
+		d, err := reencryptionFactor(params, delegatee, ephemeral.X, ephemeral.Y, sx, sy)
+		if err != nil {
+			continue
+		}
+
+		n := delegatee.Curve.Params().N
+		rk = &ReencryptionKey{
+			RK:        new(big.Int).Mod(new(big.Int).Mul(prv.D, new(big.Int).ModInverse(d, n)), n),
+			X:         ephemeral.X,
+			Y:         ephemeral.Y,
+			Delegatee: delegatee,
+		}
+		return rk, nil
+	}
+}
+
+// Reencrypt transforms an ECIES ciphertext for the delegator into one the
+// delegatee can open with Decrypt. The body of the ciphertext is left intact.
+func Reencrypt(rk *ReencryptionKey, ct string) (ctBase64 string, err error) {
+	c, err := base64.StdEncoding.DecodeString(ct)
+	if c == nil || len(c) == 0 || err != nil {
+		err = ErrInvalidMessage
+		return
+	}
+	if c[0] == versionReencrypted {
+		err = ErrAlreadyReencrypted
+		return
+	}
+	params := paramsOf(rk.Delegatee)
+	if params == nil {
+		err = ErrUnsupportedECIESParameters
+		return
+	}
+
+	curve := rk.Delegatee.Curve
+	R, em, err := parseCiphertext(curve, params, c)
+	if err != nil {
+		return
+	}
+
+	rx, ry := curve.ScalarMult(R.X, R.Y, rk.RK.Bytes())
+	Xb := elliptic.Marshal(curve, rk.X, rk.Y)
+	Rb := elliptic.Marshal(curve, rx, ry)
+
+	out := make([]byte, 0, 1+len(Xb)+len(Rb)+len(em))
+	out = append(out, versionReencrypted)
+	out = append(out, Xb...)
+	out = append(out, Rb...)
+	out = append(out, em...)
+	ctBase64 = base64.StdEncoding.EncodeToString(out)
+	return
+}
+
+// decryptReencrypted opens a re-encrypted ciphertext: the re-encryption key's
+// ephemeral point followed by a regular ECIES ciphertext.
+func (prv *PrivateKey) decryptReencrypted(ctx context.Context, rand io.Reader, params *ECIESParams, c, s1, s2 []byte, tr *Trace) (m []byte, err error) {
+	curve := prv.PublicKey.Curve
+	xLen := 2*((curve.Params().BitSize+7)/8) + 1
+	if len(c) < xLen {
+		err = ErrInvalidMessage
+		return
+	}
+	x, y := elliptic.Unmarshal(curve, c[:xLen])
+	if x == nil {
+		err = ErrInvalidPublicKey
+		return
+	}
+
+	R, em, err := parseCiphertext(curve, params, c[xLen:])
+	if err != nil {
+		return
+	}
+
+	sx, sy := curve.ScalarMult(x, y, prv.D.Bytes())
+	d, err := reencryptionFactor(params, &prv.PublicKey, x, y, sx, sy)
+	if err != nil {
+		return
+	}
+	tr.record("X", c[:xLen], false)
+	tr.record("d", d.Bytes(), true)
+	tr.record("R'", elliptic.Marshal(curve, R.X, R.Y), false)
+
+	factor := &PrivateKey{PublicKey: PublicKey{Curve: curve}, D: d}
+	_, span := tracer.Start(ctx, "ecies.GenerateShared")
+	z, err := factor.GenerateShared(R, params.KeyLen, params.MacLen)
+	endSpan(span, err)
+	if err != nil {
+		return
+	}
+
+	tr.record("z", z, true)
+
+	return openMessage(ctx, rand, params, z, em, s1, s2, tr)
+}
+
+type asnReencryptionKey struct {
+	Version   asnECPrivKeyVer
+	RK        []byte
+	Ephemeral asn1.BitString
+	Delegatee asn1.BitString
+}
+
+// MarshalReencryptionKey Encode a re-encryption key to DER format.
+func MarshalReencryptionKey(rk *ReencryptionKey) ([]byte, error) {
+	pub, err := MarshalPublic(rk.Delegatee)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral := elliptic.Marshal(rk.Delegatee.Curve, rk.X, rk.Y)
+	return asn1.Marshal(asnReencryptionKey{
+		Version: asnECPrivKeyVer1,
+		RK:      rk.RK.Bytes(),
+		Ephemeral: asn1.BitString{
+			BitLength: len(ephemeral) * 8,
+			Bytes:     ephemeral,
+		},
+		Delegatee: asn1.BitString{
+			BitLength: len(pub) * 8,
+			Bytes:     pub,
+		},
+	})
+}
+
+// UnmarshalReencryptionKey Decode a DER-encoded re-encryption key.
+func UnmarshalReencryptionKey(in []byte) (rk *ReencryptionKey, err error) {
+	var asnRK asnReencryptionKey
+
+	if _, err = asn1.Unmarshal(in, &asnRK); err != nil {
+		return
+	} else if asnRK.Version != asnECPrivKeyVer1 {
+		err = ErrInvalidReencryptionKey
+		return
+	}
+
+	delegatee, err := UnmarshalPublic(asnRK.Delegatee.Bytes)
+	if err != nil {
+		return
+	}
+
+	rk = &ReencryptionKey{
+		RK:        new(big.Int).SetBytes(asnRK.RK),
+		Delegatee: delegatee,
+	}
+	rk.X, rk.Y = elliptic.Unmarshal(delegatee.Curve, asnRK.Ephemeral.Bytes)
+	if rk.X == nil || rk.RK.Sign() == 0 {
+		return nil, ErrInvalidReencryptionKey
+	}
+	return
+}
+
+// ExportReencryptionKeyPEM Export a re-encryption key to PEM format.
+func ExportReencryptionKeyPEM(rk *ReencryptionKey) (out []byte, err error) {
+	der, err := MarshalReencryptionKey(rk)
+	if err != nil {
+		return
+	}
+
+	var block pem.Block
+	block.Type = "ELLIPTIC CURVE REENCRYPTION KEY"
+	block.Bytes = der
+
+	buf := new(bytes.Buffer)
+	err = pem.Encode(buf, &block)
+	if err != nil {
+		return
+	} else {
+		out = buf.Bytes()
+	}
+	return
+}
+
+// ImportReencryptionKeyPEM Import a PEM-encoded re-encryption key.
+func ImportReencryptionKeyPEM(in []byte) (rk *ReencryptionKey, err error) {
+	p, _ := pem.Decode(in)
+	if p == nil || p.Type != "ELLIPTIC CURVE REENCRYPTION KEY" {
+		return nil, ErrInvalidReencryptionKey
+	}
+
+	rk, err = UnmarshalReencryptionKey(p.Bytes)
+	return
+}
@@ -0,0 +1,293 @@
+package cypher
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrCurveRegistered = fmt.Errorf("ecies: curve is already registered")
+	ErrSuiteRegistered = fmt.Errorf("ecies: suite is already registered")
+	ErrUnknownSuite    = fmt.Errorf("ecies: unknown default suite")
+)
+
+// Curve describes an elliptic curve known to the package: the name it is
+// selected by, its named curve OID and the suite used when a key is created
+// without explicit parameters.
+type Curve struct {
+	Name         string
+	Curve        elliptic.Curve
+	OID          asn1.ObjectIdentifier
+	DefaultSuite string
+}
+
+// Suite describes a named set of ECIES parameters together with the OIDs used
+// to encode it in a public key (see SEC 1, C.5).
+type Suite struct {
+	Name        string
+	Description string
+	Params      *ECIESParams
+	ECDH        asn1.ObjectIdentifier
+	Sym         asn1.ObjectIdentifier
+}
+
+var registry = struct {
+	sync.RWMutex
+	curves []Curve
+	suites []Suite
+}{}
+
+// RegisterCurve makes a curve available for key generation and marshalling.
+// The default suite, if any, must be registered first.
+func RegisterCurve(c Curve) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, known := range registry.curves {
+		if known.Name == c.Name || known.Curve == c.Curve || known.OID.Equal(c.OID) {
+			return ErrCurveRegistered
+		}
+	}
+	if c.DefaultSuite != "" {
+		if _, ok := suiteByName(c.DefaultSuite); !ok {
+			return ErrUnknownSuite
+		}
+	}
+	registry.curves = append(registry.curves, c)
+	return nil
+}
+
+// RegisterSuite makes a parameter suite available for key generation and
+// marshalling.
+func RegisterSuite(s Suite) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, known := range registry.suites {
+		if known.Name == s.Name || known.Params == s.Params {
+			return ErrSuiteRegistered
+		}
+		if known.ECDH.Equal(s.ECDH) && known.Sym.Equal(s.Sym) {
+			return ErrSuiteRegistered
+		}
+	}
+	registry.suites = append(registry.suites, s)
+	return nil
+}
+
+// Curves returns the registered curves in registration order.
+func Curves() []Curve {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Curve(nil), registry.curves...)
+}
+
+// Suites returns the registered suites in registration order.
+func Suites() []Suite {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Suite(nil), registry.suites...)
+}
+
+// CurveByName looks up a registered curve by its name.
+func CurveByName(name string) (Curve, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, c := range registry.curves {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Curve{}, false
+}
+
+// LookupCurve returns the registry entry of an elliptic curve.
+func LookupCurve(curve elliptic.Curve) (Curve, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, c := range registry.curves {
+		if c.Curve == curve {
+			return c, true
+		}
+	}
+	return Curve{}, false
+}
+
+func curveByOID(oid asn1.ObjectIdentifier) (Curve, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, c := range registry.curves {
+		if c.OID.Equal(oid) {
+			return c, true
+		}
+	}
+	return Curve{}, false
+}
+
+// SuiteByName looks up a registered suite by its name.
+func SuiteByName(name string) (Suite, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	return suiteByName(name)
+}
+
+func suiteByName(name string) (Suite, bool) {
+	for _, s := range registry.suites {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Suite{}, false
+}
+
+// LookupSuite returns the registry entry matching the parameters. Parameters
+// decoded from a key are matched field by field, so they do not need to be
+// the registered instance.
+func LookupSuite(params *ECIESParams) (Suite, bool) {
+	if params == nil {
+		return Suite{}, false
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, s := range registry.suites {
+		if s.Params == params {
+			return s, true
+		}
+	}
+	for _, s := range registry.suites {
+		if sameParams(s.Params, params) {
+			return s, true
+		}
+	}
+	return Suite{}, false
+}
+
+// Supports reports whether keys on the curve can use the suite, that is
+// whether ECDH on the curve yields enough key material for the suite's
+// cipher and MAC keys.
+func (c Curve) Supports(s Suite) bool {
+	return s.Params.KeyLen+s.Params.MacLen <= (c.Curve.Params().BitSize+7)/8
+}
+
+// securityLevels are the security strengths of NIST SP 800-57, Part 1.
+var securityLevels = []int{256, 192, 128, 112, 80}
+
+// SecurityBits returns the security strength of the curve in bits: the
+// highest level of NIST SP 800-57 not above half the size of its order.
+func (c Curve) SecurityBits() int {
+	half := c.Curve.Params().BitSize / 2
+	for _, level := range securityLevels {
+		if level <= half {
+			return level
+		}
+	}
+	return half
+}
+
+// SecurityBits returns the security level of the suite in bits, the weaker
+// of its cipher key and its MAC tag.
+func (s Suite) SecurityBits() int {
+	return 8 * min(s.Params.KeyLen, s.Params.MacLen)
+}
+
+// HashName returns the name of the suite's KDF and MAC hash, e.g. "SHA-256".
+func (s Suite) HashName() string {
+	return s.Params.hashAlgo.String()
+}
+
+func suiteByOIDs(ecdh, sym asn1.ObjectIdentifier) (Suite, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, s := range registry.suites {
+		if s.ECDH.Equal(ecdh) && s.Sym.Equal(sym) {
+			return s, true
+		}
+	}
+	return Suite{}, false
+}
+
+func sameParams(a, b *ECIESParams) bool {
+	return a.hashAlgo == b.hashAlgo &&
+		a.BlockSize == b.BlockSize &&
+		a.KeyLen == b.KeyLen &&
+		a.MacLen == b.MacLen
+}
+
+func mustRegister(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	mustRegister(RegisterSuite(Suite{
+		Name:        "aes128-sha256",
+		Description: "AES128 HMAC-SHA-256-16",
+		Params:      EciesAes128Sha256,
+		ECDH:        dhsinglepassStddhSha256kdf.Algorithm,
+		Sym:         aes128CTRinECIES.Algorithm,
+	}))
+	mustRegister(RegisterSuite(Suite{
+		Name:        "aes256-sha256",
+		Description: "AES256 HMAC-SHA-256-32",
+		Params:      EciesAes256Sha256,
+		ECDH:        dhsinglepassStddhSha256kdf.Algorithm,
+		Sym:         aes256CTRinECIES.Algorithm,
+	}))
+	mustRegister(RegisterSuite(Suite{
+		Name:        "aes256-sha384",
+		Description: "AES256 HMAC-SHA-384-48",
+		Params:      EciesAes256Sha384,
+		ECDH:        dhsinglepassStddhSha384kdf.Algorithm,
+		Sym:         aes256CTRinECIES.Algorithm,
+	}))
+	mustRegister(RegisterSuite(Suite{
+		Name:        "aes256-sha512",
+		Description: "AES256 HMAC-SHA-512-64",
+		Params:      EciesAes256Sha512,
+		ECDH:        dhsinglepassStddhSha512kdf.Algorithm,
+		Sym:         aes256CTRinECIES.Algorithm,
+	}))
+
+	// RFC 5480, 2.1.1.1. Named Curve
+	//
+	//	secp224r1 OBJECT IDENTIFIER ::= {
+	//	  iso(1) identified-organization(3) certicom(132) curve(0) 33 }
+	//
+	//	secp256r1 OBJECT IDENTIFIER ::= {
+	//	  iso(1) member-body(2) us(840) ansi-X9-62(10045) curves(3)
+	//	  prime(1) 7 }
+	//
+	//	secp384r1 OBJECT IDENTIFIER ::= {
+	//	  iso(1) identified-organization(3) certicom(132) curve(0) 34 }
+	//
+	//	secp521r1 OBJECT IDENTIFIER ::= {
+	//	  iso(1) identified-organization(3) certicom(132) curve(0) 35 }
+	//
+	// NB: secp256r1 is equivalent to prime256v1
+	mustRegister(RegisterCurve(Curve{
+		Name:         "P-256",
+		Curve:        elliptic.P256(),
+		OID:          asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7},
+		DefaultSuite: "aes128-sha256",
+	}))
+	mustRegister(RegisterCurve(Curve{
+		Name:         "P-384",
+		Curve:        elliptic.P384(),
+		OID:          asn1.ObjectIdentifier{1, 3, 132, 0, 34},
+		DefaultSuite: "aes256-sha384",
+	}))
+	mustRegister(RegisterCurve(Curve{
+		Name:         "P-521",
+		Curve:        elliptic.P521(),
+		OID:          asn1.ObjectIdentifier{1, 3, 132, 0, 35},
+		DefaultSuite: "aes256-sha512",
+	}))
+	mustRegister(RegisterCurve(Curve{
+		Name:  "P-224",
+		Curve: elliptic.P224(),
+		OID:   asn1.ObjectIdentifier{1, 3, 132, 0, 33},
+	}))
+}
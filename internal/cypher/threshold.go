@@ -0,0 +1,354 @@
+package cypher
+
+import (
+	"bytes"
+	"context"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+var (
+	ErrInvalidThreshold   = fmt.Errorf("ecies: invalid threshold parameters")
+	ErrInvalidKeyShare    = fmt.Errorf("ecies: invalid key share")
+	ErrNotEnoughShares    = fmt.Errorf("ecies: not enough decryption shares")
+	ErrDuplicateShare     = fmt.Errorf("ecies: duplicate decryption share")
+	ErrInvalidShareCurve  = fmt.Errorf("ecies: decryption share is on a different curve")
+	ErrInvalidDecryptPart = fmt.Errorf("ecies: invalid decryption share")
+)
+
+// KeyShare is one Shamir share of a private scalar, split over the curve
+// order. It carries the full public key so that shareholders can check which
+// key and parameters it belongs to.
+type KeyShare struct {
+	PublicKey
+	Index     int
+	Threshold int
+	D         *big.Int
+}
+
+// DecryptionShare is the partial ECDH value d_i·R computed by one shareholder
+// for the ephemeral point R of a ciphertext.
+type DecryptionShare struct {
+	Curve     elliptic.Curve
+	Index     int
+	Threshold int
+	X         *big.Int
+	Y         *big.Int
+}
+
+// SplitKey splits the private scalar into n shares, any threshold of which
+// can decrypt together. The scalar itself is never needed again: shares are
+// combined at the level of ECDH values in CombineShares.
+func SplitKey(rand io.Reader, prv *PrivateKey, threshold, n int) (shares []*KeyShare, err error) {
+	order := prv.PublicKey.Curve.Params().N
+	if threshold < 1 || n < threshold || big.NewInt(int64(n)).Cmp(order) >= 0 {
+		return nil, ErrInvalidThreshold
+	}
+
+	// f(x) = D + a_1·x + ... + a_{t-1}·x^{t-1} mod N
+	coefficients := make([]*big.Int, threshold)
+	coefficients[0] = new(big.Int).Set(prv.D)
+	for i := 1; i < threshold; i++ {
+		if coefficients[i], err = randFieldElement(rand, order); err != nil {
+			return nil, err
+		}
+	}
+
+	shares = make([]*KeyShare, n)
+	for i := 1; i <= n; i++ {
+		x := big.NewInt(int64(i))
+		y := new(big.Int)
+		for j := threshold - 1; j >= 0; j-- {
+			y.Mul(y, x)
+			y.Add(y, coefficients[j])
+			y.Mod(y, order)
+		}
+		shares[i-1] = &KeyShare{
+			PublicKey: prv.PublicKey,
+			Index:     i,
+			Threshold: threshold,
+			D:         y,
+		}
+	}
+	return shares, nil
+}
+
+// randFieldElement returns a uniformly random scalar in [1, N-1].
+func randFieldElement(rand io.Reader, order *big.Int) (*big.Int, error) {
+	b := make([]byte, (order.BitLen()+7)/8+8)
+	if _, err := io.ReadFull(rand, b); err != nil {
+		return nil, err
+	}
+	k := new(big.Int).SetBytes(b)
+	n := new(big.Int).Sub(order, big.NewInt(1))
+	k.Mod(k, n)
+	k.Add(k, big.NewInt(1))
+	return k, nil
+}
+
+// PartialDecrypt computes this share's ECDH value for the ephemeral point of
+// an ECIES ciphertext.
+func (share *KeyShare) PartialDecrypt(ct string) (part *DecryptionShare, err error) {
+	c, err := base64.StdEncoding.DecodeString(ct)
+	if c == nil || len(c) == 0 || err != nil {
+		return nil, ErrInvalidMessage
+	}
+	params := paramsOf(&share.PublicKey)
+	if params == nil {
+		return nil, ErrUnsupportedECIESParameters
+	}
+
+	R, _, err := parseCiphertext(share.PublicKey.Curve, params, c)
+	if err != nil {
+		return
+	}
+
+	part = &DecryptionShare{
+		Curve:     share.PublicKey.Curve,
+		Index:     share.Index,
+		Threshold: share.Threshold,
+	}
+	part.X, part.Y = share.PublicKey.Curve.ScalarMult(R.X, R.Y, share.D.Bytes())
+	return
+}
+
+// CombineShares interpolates the decryption shares at zero to obtain D·R and
+// finishes the ECIES decryption with the existing KDF and MAC steps.
+func CombineShares(rand io.Reader, pub *PublicKey, ct string, parts []*DecryptionShare, s1, s2 []byte) (m []byte, err error) {
+	c, err := base64.StdEncoding.DecodeString(ct)
+	if c == nil || len(c) == 0 || err != nil {
+		return nil, ErrInvalidMessage
+	}
+	params := paramsOf(pub)
+	if params == nil {
+		return nil, ErrUnsupportedECIESParameters
+	}
+	_, em, err := parseCiphertext(pub.Curve, params, c)
+	if err != nil {
+		return
+	}
+
+	// The threshold is taken from the shares, so they must all agree on it:
+	// interpolating fewer points than the polynomial degree needs yields a
+	// wrong point instead of an error.
+	if len(parts) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+	threshold := parts[0].Threshold
+	if threshold < 1 {
+		return nil, ErrInvalidDecryptPart
+	}
+	seen := make(map[int]bool, len(parts))
+	for _, part := range parts {
+		if part == nil || part.X == nil || part.Y == nil {
+			return nil, ErrInvalidDecryptPart
+		}
+		if part.Curve != pub.Curve {
+			return nil, ErrInvalidShareCurve
+		}
+		if part.Threshold != threshold || part.Index < 1 || !pub.Curve.IsOnCurve(part.X, part.Y) {
+			return nil, ErrInvalidDecryptPart
+		}
+		if seen[part.Index] {
+			return nil, ErrDuplicateShare
+		}
+		seen[part.Index] = true
+	}
+	if len(parts) < threshold {
+		return nil, ErrNotEnoughShares
+	}
+
+	order := pub.Curve.Params().N
+	var x, y *big.Int
+	for _, part := range parts {
+		lambda := lagrangeAtZero(order, part.Index, parts)
+		px, py := pub.Curve.ScalarMult(part.X, part.Y, lambda.Bytes())
+		if x == nil {
+			x, y = px, py
+		} else {
+			x, y = pub.Curve.Add(x, y, px, py)
+		}
+	}
+	if x == nil || (x.Sign() == 0 && y.Sign() == 0) {
+		return nil, ErrSharedKeyIsPointAtInfinity
+	}
+
+	return openMessage(context.Background(), rand, params, sharedFromX(pub, x), em, s1, s2, nil)
+}
+
+// lagrangeAtZero computes the Lagrange coefficient of share i for
+// interpolating at x = 0 over the indices of parts.
+func lagrangeAtZero(order *big.Int, i int, parts []*DecryptionShare) *big.Int {
+	num := big.NewInt(1)
+	den := big.NewInt(1)
+	xi := big.NewInt(int64(i))
+	for _, part := range parts {
+		if part.Index == i {
+			continue
+		}
+		xj := big.NewInt(int64(part.Index))
+		num.Mul(num, xj)
+		num.Mod(num, order)
+		den.Mul(den, new(big.Int).Sub(xj, xi))
+		den.Mod(den, order)
+	}
+	den.ModInverse(den, order)
+	return num.Mul(num, den).Mod(num, order)
+}
+
+type asnKeyShare struct {
+	Version   asnECPrivKeyVer
+	Index     int
+	Threshold int
+	Private   []byte
+	Public    asn1.BitString
+}
+
+type asnDecryptionShare struct {
+	Index     int
+	Threshold int
+	Curve     secgNamedCurve
+	Point     asn1.BitString
+}
+
+// MarshalKeyShare Encode a key share to DER format.
+func MarshalKeyShare(share *KeyShare) ([]byte, error) {
+	pub, err := MarshalPublic(&share.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(asnKeyShare{
+		Version:   asnECPrivKeyVer1,
+		Index:     share.Index,
+		Threshold: share.Threshold,
+		Private:   share.D.Bytes(),
+		Public: asn1.BitString{
+			BitLength: len(pub) * 8,
+			Bytes:     pub,
+		},
+	})
+}
+
+// UnmarshalKeyShare Decode a DER-encoded key share.
+func UnmarshalKeyShare(in []byte) (share *KeyShare, err error) {
+	var asnShare asnKeyShare
+
+	if _, err = asn1.Unmarshal(in, &asnShare); err != nil {
+		return
+	} else if asnShare.Version != asnECPrivKeyVer1 || asnShare.Index < 1 || asnShare.Threshold < 1 {
+		err = ErrInvalidKeyShare
+		return
+	}
+
+	pub, err := UnmarshalPublic(asnShare.Public.Bytes)
+	if err != nil {
+		return
+	}
+	share = &KeyShare{
+		PublicKey: *pub,
+		Index:     asnShare.Index,
+		Threshold: asnShare.Threshold,
+		D:         new(big.Int).SetBytes(asnShare.Private),
+	}
+	return
+}
+
+// MarshalDecryptionShare Encode a decryption share to DER format.
+func MarshalDecryptionShare(part *DecryptionShare) ([]byte, error) {
+	curve, ok := oidFromNamedCurve(part.Curve)
+	if !ok {
+		return nil, ErrInvalidCurve
+	}
+	point := elliptic.Marshal(part.Curve, part.X, part.Y)
+	return asn1.Marshal(asnDecryptionShare{
+		Index:     part.Index,
+		Threshold: part.Threshold,
+		Curve:     curve,
+		Point: asn1.BitString{
+			BitLength: len(point) * 8,
+			Bytes:     point,
+		},
+	})
+}
+
+// UnmarshalDecryptionShare Decode a DER-encoded decryption share.
+func UnmarshalDecryptionShare(in []byte) (part *DecryptionShare, err error) {
+	var asnPart asnDecryptionShare
+
+	if _, err = asn1.Unmarshal(in, &asnPart); err != nil {
+		return
+	}
+	curve := namedCurveFromOID(asnPart.Curve)
+	if curve == nil {
+		return nil, ErrInvalidCurve
+	}
+
+	part = &DecryptionShare{
+		Curve:     curve,
+		Index:     asnPart.Index,
+		Threshold: asnPart.Threshold,
+	}
+	part.X, part.Y = elliptic.Unmarshal(curve, asnPart.Point.Bytes)
+	if part.X == nil {
+		return nil, ErrInvalidDecryptPart
+	}
+	return
+}
+
+// ExportKeySharePEM Export a key share to PEM format.
+func ExportKeySharePEM(share *KeyShare) (out []byte, err error) {
+	der, err := MarshalKeyShare(share)
+	if err != nil {
+		return
+	}
+	return encodePEM("ELLIPTIC CURVE PRIVATE KEY SHARE", der)
+}
+
+// ImportKeySharePEM Import a PEM-encoded key share.
+func ImportKeySharePEM(in []byte) (share *KeyShare, err error) {
+	p, _ := pem.Decode(in)
+	if p == nil || p.Type != "ELLIPTIC CURVE PRIVATE KEY SHARE" {
+		return nil, ErrInvalidKeyShare
+	}
+
+	share, err = UnmarshalKeyShare(p.Bytes)
+	return
+}
+
+// ExportDecryptionSharePEM Export a decryption share to PEM format.
+func ExportDecryptionSharePEM(part *DecryptionShare) (out []byte, err error) {
+	der, err := MarshalDecryptionShare(part)
+	if err != nil {
+		return
+	}
+	return encodePEM("ELLIPTIC CURVE DECRYPTION SHARE", der)
+}
+
+// ImportDecryptionSharePEM Import a PEM-encoded decryption share.
+func ImportDecryptionSharePEM(in []byte) (part *DecryptionShare, err error) {
+	p, _ := pem.Decode(in)
+	if p == nil || p.Type != "ELLIPTIC CURVE DECRYPTION SHARE" {
+		return nil, ErrInvalidDecryptPart
+	}
+
+	part, err = UnmarshalDecryptionShare(p.Bytes)
+	return
+}
+
+func encodePEM(blockType string, der []byte) (out []byte, err error) {
+	var block pem.Block
+	block.Type = blockType
+	block.Bytes = der
+
+	buf := new(bytes.Buffer)
+	err = pem.Encode(buf, &block)
+	if err != nil {
+		return
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,27 @@
+package cypher
+
+// TraceStep is an intermediate value of an encryption or decryption. Secret
+// marks values that reveal the key or the plaintext and must not leave a
+// trusted environment.
+type TraceStep struct {
+	Name   string
+	Value  []byte
+	Secret bool
+}
+
+// Trace records the steps of an ECIES operation, in order. A nil *Trace
+// records nothing, so the regular code paths pass nil.
+type Trace struct {
+	Steps []TraceStep
+}
+
+func (tr *Trace) record(name string, value []byte, secret bool) {
+	if tr == nil {
+		return
+	}
+	tr.Steps = append(tr.Steps, TraceStep{
+		Name:   name,
+		Value:  append([]byte(nil), value...),
+		Secret: secret,
+	})
+}
@@ -0,0 +1,20 @@
+package cypher
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records spans around the expensive steps of ECIES. Until a tracer
+// provider is installed, the spans cost next to nothing.
+var tracer = otel.Tracer("github.com/axidex/elliptic/internal/cypher")
+
+// endSpan marks the span as failed if err is set and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,92 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"os"
+
+	"github.com/axidex/elliptic/internal/cypher"
+)
+
+// KeySuffix is appended to the name of an encrypted file to get the file
+// holding its wrapped data key.
+const KeySuffix = ".key"
+
+// DataKeySize is the size of the AES-256 data keys used for files.
+const DataKeySize = 32
+
+var ErrCorrupted = errors.New("envelope: encrypted file is corrupted")
+
+// EncryptFile encrypts src with a fresh data key and writes the result to dst.
+// The data key, wrapped to pub with ECIES, is written to dst+KeySuffix.
+//
+// The file is sealed with AES-256-GCM as nonce || ciphertext, with the
+// wrapped key as additional data, so a file can't be paired with another key.
+func EncryptFile(pub *cypher.PublicKey, src, dst string) error {
+	plaintext, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	key, wrapped, err := cypher.GenerateDataKey(rand.Reader, pub, DataKeySize)
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(wrapped))
+
+	if err = os.WriteFile(dst+KeySuffix, []byte(wrapped), 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, sealed, 0o600)
+}
+
+// DecryptFile reverses EncryptFile: it unwraps the data key stored next to
+// src with prv and writes the plaintext to dst.
+func DecryptFile(prv *cypher.PrivateKey, src, dst string) error {
+	wrapped, err := os.ReadFile(src + KeySuffix)
+	if err != nil {
+		return err
+	}
+	sealed, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	key, err := prv.UnwrapDataKey(rand.Reader, string(wrapped))
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	n := aead.NonceSize()
+	if len(sealed) < n {
+		return ErrCorrupted
+	}
+	plaintext, err := aead.Open(nil, sealed[:n], sealed[n:], wrapped)
+	if err != nil {
+		return ErrCorrupted
+	}
+	return os.WriteFile(dst, plaintext, 0o600)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -17,8 +17,10 @@ type AppGui struct {
 	privateKeyEntry, publicKeyEntry      *widget.Entry
 	openText, closedText                 *widget.Entry
 	curveInfoEntry, eciesInfo            *widget.Entry
+	stepsEntry                           *widget.Entry
 	encrypt, decrypt, generateKeysButton *widget.Button
-	selectCurve                          *widget.Select
+	selectCurve, selectScheme            *widget.Select
+	showSteps                            *widget.Check
 	w                                    fyne.Window
 
 	width, height float32
@@ -43,11 +45,16 @@ func NewAppGui(app fyne.App, logger logger.Logger) AppGui {
 }
 
 func (app *AppGui) initSelect() {
-	//app.selectParams = widget.NewSelect(ParamNames, func(string) {})
+	//app.selectParams = widget.NewSelect(ParamNames(), func(string) {})
 	//app.selectParams.SetSelectedIndex(0)
 
-	app.selectCurve = widget.NewSelect(CurveNames, func(string) {})
+	app.selectCurve = widget.NewSelect(CurveNames(), func(string) {})
 	app.selectCurve.SetSelectedIndex(0)
+
+	app.selectScheme = widget.NewSelect(SchemeNames, func(string) {})
+	app.selectScheme.SetSelectedIndex(0)
+
+	app.showSteps = widget.NewCheck("Show steps", app.toggleSteps)
 }
 
 func (app *AppGui) initEntry() {
@@ -72,6 +79,10 @@ func (app *AppGui) initEntry() {
 	initEntry(app.eciesInfo, "ECIES Info", 7)
 	app.eciesInfo.Disable()
 
+	app.stepsEntry = widget.NewMultiLineEntry()
+	initEntry(app.stepsEntry, "Steps", 12)
+	app.stepsEntry.Disable()
+	app.stepsEntry.Hide()
 }
 
 func initEntry(entry *widget.Entry, name string, numberOfLines int) {
@@ -113,10 +124,10 @@ func (app *AppGui) Run() {
 
 	bottomContainer := container.NewGridWrap(
 		fyne.NewSize(app.width, app.generateKeysButton.MinSize().Height),
-		app.generateKeysButton, app.selectCurve,
+		app.generateKeysButton, app.selectCurve, app.selectScheme, app.showSteps,
 	)
 
-	appContainer := container.NewVBox(topContainer, bottomContainer)
+	appContainer := container.NewVBox(topContainer, bottomContainer, app.stepsEntry)
 
 	app.w.SetContent(appContainer)
 
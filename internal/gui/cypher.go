@@ -73,7 +73,20 @@ func (app *AppGui) encryptData() {
 
 	app.setPublicKeyInfo(key)
 
-	encryptedText, err := cypher.Encrypt(rand.Reader, key, []byte(text), nil, nil)
+	scheme := SchemeName(app.selectScheme.Selected)
+	var (
+		encryptedText string
+		trace         *cypher.Trace
+	)
+	if app.showSteps.Checked {
+		encryptedText, trace, err = scheme.EncryptTrace(key, []byte(text))
+	} else {
+		encryptedText, err = scheme.Encrypt(key, []byte(text))
+	}
+	// Шаги показываются и при ошибке, например при несовпадении тега
+	if app.showSteps.Checked {
+		app.setSteps(trace)
+	}
 	if err != nil {
 		app.logger.Errorf("Encryption error %v", err)
 		dialog.ShowError(err, app.w)
@@ -101,7 +114,20 @@ func (app *AppGui) decryptData() {
 
 	//app.logger.Infof("Decrypting data %s", encryptedBytes)
 
-	decryptText, err := keys.Decrypt(rand.Reader, encryptedBytes, nil, nil)
+	scheme := SchemeName(app.selectScheme.Selected)
+	var (
+		decryptText []byte
+		trace       *cypher.Trace
+	)
+	if app.showSteps.Checked {
+		decryptText, trace, err = scheme.DecryptTrace(keys, encryptedBytes)
+	} else {
+		decryptText, err = scheme.Decrypt(keys, encryptedBytes)
+	}
+	// Шаги показываются и при ошибке, например при несовпадении тега
+	if app.showSteps.Checked {
+		app.setSteps(trace)
+	}
 	if err != nil {
 		app.logger.Infof("Decryption error %v", err)
 		dialog.ShowError(err, app.w)
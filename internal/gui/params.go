@@ -2,85 +2,119 @@ package gui
 
 import (
 	"crypto/elliptic"
+	"crypto/rand"
 	"github.com/axidex/elliptic/internal/cypher"
 )
 
 type CurveName string
 
-const (
-	curveP256 = "P256"
-	curveP384 = "P384"
-	curveP521 = "P521"
-)
-
-var CurveNames = []string{
-	curveP256,
-	curveP384,
-	//curveP521,
+// CurveNames lists the registered curves that have a default suite, so a key
+// generated for any of them can be used right away.
+func CurveNames() []string {
+	var names []string
+	for _, curve := range cypher.Curves() {
+		if curve.DefaultSuite != "" {
+			names = append(names, curve.Name)
+		}
+	}
+	return names
 }
 
 func GetNameByCurve(curve elliptic.Curve) CurveName {
-	switch curve {
-	case elliptic.P384():
-		return curveP384
-	case elliptic.P521():
-		return curveP521
-	default:
-		return curveP256
-
+	if c, ok := cypher.LookupCurve(curve); ok {
+		return CurveName(c.Name)
 	}
+	return CurveName(cypher.DefaultCurve.Params().Name)
 }
 
 func (name CurveName) GetCurveByName() elliptic.Curve {
-	switch name {
-	case curveP384:
-		return elliptic.P384()
-	case curveP521:
-		return elliptic.P521()
-	default:
-		return elliptic.P256()
+	if c, ok := cypher.CurveByName(string(name)); ok {
+		return c.Curve
 	}
+	return cypher.DefaultCurve
 }
 
 type ParamName string
 
+// ParamNames lists the registered suites.
+func ParamNames() []string {
+	var names []string
+	for _, suite := range cypher.Suites() {
+		names = append(names, suite.Name)
+	}
+	return names
+}
+
+// GetNameByParam returns the suite name of the parameters, the inverse of
+// GetParamByName.
+func GetNameByParam(param *cypher.ECIESParams) ParamName {
+	if suite, ok := cypher.LookupSuite(param); ok {
+		return ParamName(suite.Name)
+	}
+	return "unknown"
+}
+
+func (name ParamName) GetParamByName() *cypher.ECIESParams {
+	if suite, ok := cypher.SuiteByName(string(name)); ok {
+		return suite.Params
+	}
+	return cypher.ParamsFromCurve(cypher.DefaultCurve)
+}
+
+type SchemeName string
+
 const (
-	paramAes128Sha256 = "AES128 HMAC-SHA-256-16"
-	paramAes256Sha256 = "AES256 HMAC-SHA-256-32"
-	paramAes256Sha384 = "AES256 HMAC-SHA-384-48"
-	paramAes256Sha512 = "AES256 HMAC-SHA-512-64"
+	schemeECIES           = "ECIES"
+	schemeElGamal         = "EC-ElGamal"
+	schemeMenezesVanstone = "Menezes–Vanstone"
 )
 
-var ParamNames = []string{
-	paramAes128Sha256,
-	paramAes256Sha256,
-	paramAes256Sha384,
-	paramAes256Sha512,
+var SchemeNames = []string{
+	schemeECIES,
+	schemeElGamal,
+	schemeMenezesVanstone,
 }
 
-func GetNameByParam(param *cypher.ECIESParams) ParamName {
-	if param.MacLen == 16 && param.KeyLen == 16 {
-		return paramAes128Sha256
-	} else if param.MacLen == 16 && param.KeyLen == 32 {
-		return paramAes256Sha256
-	} else if param.MacLen == 16 && param.KeyLen == 32 {
-		return paramAes256Sha384
-	} else if param.MacLen == 32 && param.KeyLen == 32 {
-		return paramAes256Sha512
+// Encrypt encrypts the text with the scheme, using the same key types for
+// all of them.
+func (name SchemeName) Encrypt(key *cypher.PublicKey, text []byte) (string, error) {
+	switch name {
+	case schemeElGamal:
+		return cypher.EncryptElGamal(rand.Reader, key, text)
+	case schemeMenezesVanstone:
+		return cypher.EncryptMenezesVanstone(rand.Reader, key, text)
+	default:
+		return cypher.Encrypt(rand.Reader, key, text, nil, nil)
 	}
-
-	return paramAes128Sha256
 }
 
-func (name ParamName) GetParamByName() *cypher.ECIESParams {
+// Decrypt decrypts a ciphertext produced by Encrypt with the same scheme.
+func (name SchemeName) Decrypt(key *cypher.PrivateKey, ct string) ([]byte, error) {
 	switch name {
-	case paramAes256Sha256:
-		return cypher.EciesAes256Sha256
-	case paramAes256Sha384:
-		return cypher.EciesAes256Sha384
-	case paramAes256Sha512:
-		return cypher.EciesAes256Sha512
+	case schemeElGamal:
+		return key.DecryptElGamal(ct)
+	case schemeMenezesVanstone:
+		return key.DecryptMenezesVanstone(ct)
 	default:
-		return cypher.EciesAes128Sha256
+		return key.Decrypt(rand.Reader, ct, nil, nil)
+	}
+}
+
+// EncryptTrace works like Encrypt and also returns the recorded steps. Only
+// ECIES records them; the trace is nil for the other schemes.
+func (name SchemeName) EncryptTrace(key *cypher.PublicKey, text []byte) (string, *cypher.Trace, error) {
+	if name != schemeECIES {
+		ct, err := name.Encrypt(key, text)
+		return ct, nil, err
+	}
+	return cypher.EncryptTrace(rand.Reader, key, text, nil, nil)
+}
+
+// DecryptTrace works like Decrypt and also returns the recorded steps.
+func (name SchemeName) DecryptTrace(key *cypher.PrivateKey, ct string) ([]byte, *cypher.Trace, error) {
+	if name != schemeECIES {
+		m, err := name.Decrypt(key, ct)
+		return m, nil, err
 	}
+	return key.DecryptTrace(rand.Reader, ct, nil, nil)
 }
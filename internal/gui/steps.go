@@ -0,0 +1,40 @@
+package gui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/axidex/elliptic/internal/cypher"
+)
+
+// setSteps shows the recorded steps of the last operation. Secret values are
+// marked so they are not copied around by accident.
+func (app *AppGui) setSteps(tr *cypher.Trace) {
+	if tr == nil {
+		app.stepsEntry.SetText("Steps are only recorded for ECIES")
+		return
+	}
+
+	var b strings.Builder
+	for _, step := range tr.Steps {
+		marker := ""
+		if step.Secret {
+			marker = " [SECRET]"
+		}
+		fmt.Fprintf(&b, "%s%s: %s\n", step.Name, marker, hex.EncodeToString(step.Value))
+	}
+	app.stepsEntry.SetText(strings.TrimSuffix(b.String(), "\n"))
+}
+
+// toggleSteps shows or hides the steps panel and resizes the window to fit.
+func (app *AppGui) toggleSteps(show bool) {
+	if show {
+		app.stepsEntry.Show()
+		app.height += app.stepsEntry.MinSize().Height
+	} else {
+		app.stepsEntry.Hide()
+		app.height -= app.stepsEntry.MinSize().Height
+	}
+	app.updateWindowSize()
+}
@@ -0,0 +1,141 @@
+package keystore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultPath is the directory of the file store, or the database file of the
+// SQLite store, when none is configured.
+const DefaultPath = "./tmp/keys"
+
+var validID = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// FileStore keeps every key in its own JSON file inside a directory. Files
+// are written with 0600 permissions.
+type FileStore struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// NewFileStore opens the store in dir, creating the directory if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		dir = DefaultPath
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(id string) (string, error) {
+	if !validID.MatchString(id) {
+		return "", ErrNotFound
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+func (s *FileStore) Put(_ context.Context, key Key) error {
+	path, err := s.path(key.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return ErrExists
+	} else if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+func (s *FileStore) Get(_ context.Context, id string) (Key, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return Key{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return readKey(path)
+}
+
+func readKey(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Key{}, ErrNotFound
+	} else if err != nil {
+		return Key{}, err
+	}
+	var key Key
+	err = json.Unmarshal(data, &key)
+	return key, err
+}
+
+func (s *FileStore) List(_ context.Context) ([]Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]Key, 0, len(entries))
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || !validID.MatchString(id) {
+			continue
+		}
+		key, err := readKey(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	slices.SortStableFunc(keys, func(a, b Key) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return keys, nil
+}
+
+func (s *FileStore) Delete(_ context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *FileStore) Close() error {
+	return nil
+}
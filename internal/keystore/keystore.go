@@ -0,0 +1,140 @@
+package keystore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/axidex/elliptic/config"
+	"github.com/axidex/elliptic/internal/cypher"
+)
+
+var (
+	ErrNotFound       = errors.New("keystore: key not found")
+	ErrExists         = errors.New("keystore: key already exists")
+	ErrUnknownBackend = errors.New("keystore: unknown backend")
+	ErrKeyType        = errors.New("keystore: key has a different type")
+)
+
+// hybridSuffix ends the suite name of hybrid ECDH + ML-KEM-768 keys.
+const hybridSuffix = "+mlkem768"
+
+// Key is a stored private key. Private holds the DER encoding produced by
+// cypher.MarshalPrivate, or by cypher.MarshalHybridPrivate for hybrid keys.
+type Key struct {
+	ID        string    `json:"id"`
+	Curve     string    `json:"curve"`
+	Suite     string    `json:"suite"`
+	CreatedAt time.Time `json:"createdAt"`
+	Private   []byte    `json:"private"`
+}
+
+// Store persists keys by ID.
+type Store interface {
+	Put(ctx context.Context, key Key) error
+	Get(ctx context.Context, id string) (Key, error)
+	// List returns the stored keys ordered by creation time.
+	List(ctx context.Context) ([]Key, error)
+	Delete(ctx context.Context, id string) error
+	Close() error
+}
+
+// NewKey wraps a private key into a Key with a fresh random ID.
+func NewKey(prv *cypher.PrivateKey) (Key, error) {
+	der, err := cypher.MarshalPrivate(prv)
+	if err != nil {
+		return Key{}, err
+	}
+	return newKey(&prv.PublicKey, der, "")
+}
+
+// NewHybridKey wraps a hybrid private key into a Key with a fresh random ID.
+func NewHybridKey(prv *cypher.HybridPrivateKey) (Key, error) {
+	der, err := cypher.MarshalHybridPrivate(prv)
+	if err != nil {
+		return Key{}, err
+	}
+	return newKey(&prv.PublicKey, der, hybridSuffix)
+}
+
+func newKey(pub *cypher.PublicKey, der []byte, suffix string) (Key, error) {
+	curve, ok := cypher.LookupCurve(pub.Curve)
+	if !ok {
+		return Key{}, cypher.ErrInvalidCurve
+	}
+	suite, ok := cypher.LookupSuite(pub.Params)
+	if !ok {
+		return Key{}, cypher.ErrInvalidParams
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Key{}, err
+	}
+	return Key{
+		ID:        hex.EncodeToString(id),
+		Curve:     curve.Name,
+		Suite:     suite.Name + suffix,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Private:   der,
+	}, nil
+}
+
+// Hybrid reports whether the key is a hybrid ECDH + ML-KEM-768 key.
+func (k Key) Hybrid() bool {
+	return strings.HasSuffix(k.Suite, hybridSuffix)
+}
+
+// PrivateKey decodes the stored private key. It fails with ErrKeyType for
+// hybrid keys.
+func (k Key) PrivateKey() (*cypher.PrivateKey, error) {
+	if k.Hybrid() {
+		return nil, ErrKeyType
+	}
+	return cypher.UnmarshalPrivate(k.Private)
+}
+
+// HybridPrivateKey decodes the stored hybrid private key. It fails with
+// ErrKeyType for other keys.
+func (k Key) HybridPrivateKey() (*cypher.HybridPrivateKey, error) {
+	if !k.Hybrid() {
+		return nil, ErrKeyType
+	}
+	return cypher.UnmarshalHybridPrivate(k.Private)
+}
+
+// PublicKey decodes the elliptic curve public half of the stored key. For
+// hybrid keys it leaves out the ML-KEM key.
+func (k Key) PublicKey() (*cypher.PublicKey, error) {
+	if k.Hybrid() {
+		prv, err := k.HybridPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		return &prv.PublicKey, nil
+	}
+	prv, err := k.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &prv.PublicKey, nil
+}
+
+// Open creates the store selected in the config: "file" (the default),
+// "sqlite" or "postgres".
+func Open(cfg config.KeyStore, pg config.Postgres) (Store, error) {
+	switch cfg.Backend {
+	case "", "file":
+		return NewFileStore(cfg.Path)
+	case "sqlite":
+		return OpenSQLite(cfg.Path)
+	case "postgres":
+		return OpenPostgres(pg)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownBackend, cfg.Backend)
+	}
+}
@@ -0,0 +1,100 @@
+package keystore
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+var (
+	ErrMasterKey = errors.New("keystore: master key must be 32 bytes encoded with base64")
+	ErrUnwrap    = errors.New("keystore: can't unwrap key, wrong master key?")
+)
+
+// SealedStore wraps the private keys of another store with AES-256-GCM under
+// a master key before they reach it. The key ID is bound as additional data,
+// so a wrapped key can't be moved to another ID.
+type SealedStore struct {
+	Store
+	aead cipher.AEAD
+}
+
+// ParseMasterKey decodes a base64 master key.
+func ParseMasterKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(key) != 32 {
+		return nil, ErrMasterKey
+	}
+	return key, nil
+}
+
+// NewSealedStore wraps inner with the given 32-byte master key.
+func NewSealedStore(inner Store, masterKey []byte) (*SealedStore, error) {
+	if len(masterKey) != 32 {
+		return nil, ErrMasterKey
+	}
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SealedStore{Store: inner, aead: aead}, nil
+}
+
+func (s *SealedStore) wrap(key Key) (Key, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return Key{}, err
+	}
+	key.Private = s.aead.Seal(nonce, nonce, key.Private, []byte(key.ID))
+	return key, nil
+}
+
+func (s *SealedStore) unwrap(key Key) (Key, error) {
+	n := s.aead.NonceSize()
+	if len(key.Private) < n {
+		return Key{}, ErrUnwrap
+	}
+	private, err := s.aead.Open(nil, key.Private[:n], key.Private[n:], []byte(key.ID))
+	if err != nil {
+		return Key{}, ErrUnwrap
+	}
+	key.Private = private
+	return key, nil
+}
+
+func (s *SealedStore) Put(ctx context.Context, key Key) error {
+	wrapped, err := s.wrap(key)
+	if err != nil {
+		return err
+	}
+	return s.Store.Put(ctx, wrapped)
+}
+
+func (s *SealedStore) Get(ctx context.Context, id string) (Key, error) {
+	key, err := s.Store.Get(ctx, id)
+	if err != nil {
+		return Key{}, err
+	}
+	return s.unwrap(key)
+}
+
+func (s *SealedStore) List(ctx context.Context) ([]Key, error) {
+	keys, err := s.Store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		if keys[i], err = s.unwrap(keys[i]); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,171 @@
+package keystore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/axidex/elliptic/config"
+	"github.com/lib/pq"
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// dialect holds the differences between the supported SQL databases.
+type dialect struct {
+	driver string
+	blob   string
+	// bind returns the placeholder of the n-th parameter, starting at 1.
+	bind func(n int) string
+	// duplicate reports whether err is a primary key violation.
+	duplicate func(err error) bool
+}
+
+var (
+	postgres = dialect{
+		driver: "postgres",
+		blob:   "BYTEA",
+		bind:   func(n int) string { return fmt.Sprintf("$%d", n) },
+		duplicate: func(err error) bool {
+			var pqErr *pq.Error
+			return errors.As(err, &pqErr) && pqErr.Code == "23505" // unique_violation
+		},
+	}
+	sqlite = dialect{
+		driver: "sqlite",
+		blob:   "BLOB",
+		bind:   func(int) string { return "?" },
+		duplicate: func(err error) bool {
+			var sqliteErr *sqlitedriver.Error
+			return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+		},
+	}
+)
+
+// SQLStore keeps keys in a single table of a SQL database.
+type SQLStore struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+// OpenPostgres connects to the database from the postgres section of the
+// config.
+func OpenPostgres(cfg config.Postgres) (*SQLStore, error) {
+	params := []string{
+		"host=" + cfg.URL,
+		fmt.Sprintf("port=%d", cfg.Port),
+	}
+	if cfg.User != "" {
+		params = append(params, "user="+cfg.User)
+	}
+	if cfg.Password != "" {
+		params = append(params, "password="+cfg.Password)
+	}
+	if cfg.Schema != "" {
+		params = append(params, "dbname="+cfg.Schema)
+	}
+	if cfg.SSLMode != "" {
+		params = append(params, "sslmode="+cfg.SSLMode)
+	}
+	return openSQL(postgres, strings.Join(params, " "))
+}
+
+// OpenSQLite opens or creates the database file at path.
+func OpenSQLite(path string) (*SQLStore, error) {
+	if path == "" {
+		path = DefaultPath + ".db"
+	}
+	return openSQL(sqlite, path)
+}
+
+func openSQL(d dialect, dsn string) (*SQLStore, error) {
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if d.driver == sqlite.driver {
+		// SQLite allows a single writer.
+		db.SetMaxOpenConns(1)
+	}
+
+	s := &SQLStore{db: db, dialect: d}
+	if err = s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLStore) migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS keys (
+	id         TEXT PRIMARY KEY,
+	curve      TEXT NOT NULL,
+	suite      TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	private    %s NOT NULL
+)`, s.dialect.blob))
+	return err
+}
+
+func (s *SQLStore) Put(ctx context.Context, key Key) error {
+	b := s.dialect.bind
+	query := fmt.Sprintf("INSERT INTO keys (id, curve, suite, created_at, private) VALUES (%s, %s, %s, %s, %s)",
+		b(1), b(2), b(3), b(4), b(5))
+
+	// The primary key rejects a second key with the same ID atomically.
+	_, err := s.db.ExecContext(ctx, query, key.ID, key.Curve, key.Suite, key.CreatedAt, key.Private)
+	if err != nil && s.dialect.duplicate(err) {
+		return ErrExists
+	}
+	return err
+}
+
+func (s *SQLStore) Get(ctx context.Context, id string) (Key, error) {
+	query := "SELECT id, curve, suite, created_at, private FROM keys WHERE id = " + s.dialect.bind(1)
+
+	var key Key
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&key.ID, &key.Curve, &key.Suite, &key.CreatedAt, &key.Private)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Key{}, ErrNotFound
+	}
+	return key, err
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]Key, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, curve, suite, created_at, private FROM keys ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]Key, 0)
+	for rows.Next() {
+		var key Key
+		if err = rows.Scan(&key.ID, &key.Curve, &key.Suite, &key.CreatedAt, &key.Private); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM keys WHERE id = "+s.dialect.bind(1), id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"errors"
+	"github.com/axidex/elliptic/internal/cypher"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Outcomes of a cryptographic operation.
+const (
+	OutcomeOK           = "ok"
+	OutcomeInvalidKey   = "invalid_key"
+	OutcomeMACFailure   = "mac_failure"
+	OutcomeSharedKeyBig = "shared_key_too_big"
+	OutcomeError        = "error"
+)
+
+// unknown labels operations whose key could not be resolved.
+const unknown = "unknown"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "elliptic",
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "elliptic",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	operations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "elliptic",
+		Subsystem: "cypher",
+		Name:      "operations_total",
+		Help:      "Cryptographic operations by operation, curve, suite and outcome.",
+	}, []string{"operation", "curve", "suite", "outcome"})
+
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "elliptic",
+		Subsystem: "cypher",
+		Name:      "operation_duration_seconds",
+		Help:      "Cryptographic operation latency by operation, curve and suite.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 14),
+	}, []string{"operation", "curve", "suite"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		operations,
+		operationDuration,
+	)
+}
+
+// Handler serves the collected metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a finished HTTP request. route is the route
+// pattern, not the raw path, so that path parameters don't add labels.
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = unknown
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// Outcome classifies the error returned by a cypher operation.
+func Outcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeOK
+	case errors.Is(err, cypher.ErrInvalidMessage):
+		return OutcomeMACFailure
+	case errors.Is(err, cypher.ErrSharedKeyTooBig):
+		return OutcomeSharedKeyBig
+	case errors.Is(err, cypher.ErrImport),
+		errors.Is(err, cypher.ErrInvalidPublicKey),
+		errors.Is(err, cypher.ErrInvalidCurve),
+		errors.Is(err, cypher.ErrInvalidParams),
+		errors.Is(err, cypher.ErrUnsupportedECIESParameters):
+		return OutcomeInvalidKey
+	default:
+		return OutcomeError
+	}
+}
+
+// Operation times a single cryptographic operation. Start it before the key
+// is resolved, set the key once known and finish it with Done or InvalidKey.
+type Operation struct {
+	name         string
+	curve, suite string
+	start        time.Time
+}
+
+// Start begins timing the named operation.
+func Start(name string) *Operation {
+	return &Operation{name: name, curve: unknown, suite: unknown, start: time.Now()}
+}
+
+// Key sets the curve and suite labels from the key used by the operation.
+func (op *Operation) Key(pub *cypher.PublicKey) {
+	curve, ok := cypher.LookupCurve(pub.Curve)
+	if ok {
+		op.curve = curve.Name
+	}
+	if pub.Params == nil {
+		// Keys without explicit parameters use the curve's default suite.
+		if ok && curve.DefaultSuite != "" {
+			op.suite = curve.DefaultSuite
+		}
+	} else if suite, ok := cypher.LookupSuite(pub.Params); ok {
+		op.suite = suite.Name
+	}
+}
+
+// Labels returns the curve and suite labels set by Key.
+func (op *Operation) Labels() (curve, suite string) {
+	return op.curve, op.suite
+}
+
+// Done records the operation with the outcome of err.
+func (op *Operation) Done(err error) {
+	op.record(Outcome(err))
+}
+
+// InvalidKey records an operation that failed because its key could not be
+// parsed or found.
+func (op *Operation) InvalidKey() {
+	op.record(OutcomeInvalidKey)
+}
+
+func (op *Operation) record(outcome string) {
+	operations.WithLabelValues(op.name, op.curve, op.suite, outcome).Inc()
+	operationDuration.WithLabelValues(op.name, op.curve, op.suite).Observe(time.Since(op.start).Seconds())
+}
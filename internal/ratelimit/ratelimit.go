@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"github.com/axidex/elliptic/config"
+	"golang.org/x/time/rate"
+	"sync"
+	"time"
+)
+
+// idleTimeout is how long a bucket is kept after its last request. An idle
+// bucket refills completely long before that, so dropping it loses nothing.
+const idleTimeout = 10 * time.Minute
+
+type bucketKey struct {
+	client, route string
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type limit struct {
+	rate  rate.Limit
+	burst int
+}
+
+// Limiter keeps a token bucket for every client and route pair.
+type Limiter struct {
+	mu        sync.Mutex
+	fallback  limit
+	routes    map[string]limit
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+// New creates a limiter from the server configuration. Routes without an
+// override share the default rate, but each still gets its own bucket.
+func New(cfg config.RateLimit) *Limiter {
+	l := &Limiter{
+		fallback:  newLimit(cfg.Rate, cfg.Burst),
+		routes:    make(map[string]limit, len(cfg.Routes)),
+		buckets:   make(map[bucketKey]*bucket),
+		lastSweep: time.Now(),
+	}
+	for _, route := range cfg.Routes {
+		l.routes[route.Path] = newLimit(route.Rate, route.Burst)
+	}
+	return l
+}
+
+func newLimit(r float64, burst int) limit {
+	if r <= 0 {
+		return limit{rate: rate.Inf}
+	}
+	return limit{rate: rate.Limit(r), burst: max(burst, 1)}
+}
+
+// Allow takes a token from the bucket of client on route. If the bucket is
+// empty it reports how long the client has to wait for the next token.
+func (l *Limiter) Allow(client, route string) (ok bool, retryAfter time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	b := l.bucket(client, route, now)
+	l.mu.Unlock()
+
+	r := b.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return false, time.Second
+	}
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+func (l *Limiter) bucket(client, route string, now time.Time) *bucket {
+	if now.Sub(l.lastSweep) > idleTimeout {
+		for key, b := range l.buckets {
+			if now.Sub(b.lastSeen) > idleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	key := bucketKey{client: client, route: route}
+	b, ok := l.buckets[key]
+	if !ok {
+		lim, ok := l.routes[route]
+		if !ok {
+			lim = l.fallback
+		}
+		b = &bucket{limiter: rate.NewLimiter(lim.rate, lim.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b
+}
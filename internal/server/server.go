@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/axidex/Unknown/pkg/logger"
+	"github.com/axidex/elliptic/config"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Defaults used when the configuration leaves a setting at zero.
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 15 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 60 * time.Second
+	DefaultShutdownTimeout   = 15 * time.Second
+)
+
+// Server runs the HTTP server until it is told to stop by SIGTERM or SIGINT,
+// then drains in-flight requests.
+type Server struct {
+	http   *http.Server
+	certs  *CertReloader
+	cfg    config.Server
+	logger logger.Logger
+}
+
+// New creates the server for handler. TLS is set up if enabled in cfg.
+func New(cfg config.Server, handler http.Handler, logger logger.Logger) (*Server, error) {
+	s := &Server{
+		http: &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.Port),
+			Handler:           handler,
+			ReadHeaderTimeout: orDefault(cfg.ReadHeaderTimeout, DefaultReadHeaderTimeout),
+			ReadTimeout:       orDefault(cfg.ReadTimeout, DefaultReadTimeout),
+			WriteTimeout:      orDefault(cfg.WriteTimeout, DefaultWriteTimeout),
+			IdleTimeout:       orDefault(cfg.IdleTimeout, DefaultIdleTimeout),
+			MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		},
+		cfg:    cfg,
+		logger: logger,
+	}
+
+	if cfg.TLS.Enabled {
+		tlsConfig, certs, err := NewTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		s.http.TLSConfig = tlsConfig
+		s.certs = certs
+	}
+	return s, nil
+}
+
+// Run serves until SIGTERM or SIGINT. On a signal it calls drain, so the
+// readiness check can start failing, waits for the configured drain delay to
+// let load balancers notice, and shuts down, giving in-flight requests up to
+// the shutdown timeout to finish. SIGHUP reloads the TLS certificate.
+func (s *Server) Run(drain func()) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		s.logger.Infof("Listening on %s (tls: %t)", s.http.Addr, s.certs != nil)
+		if s.certs != nil {
+			serveErr <- s.http.ListenAndServeTLS("", "")
+		} else {
+			serveErr <- s.http.ListenAndServe()
+		}
+	}()
+
+	for {
+		select {
+		case err := <-serveErr:
+			return err
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				s.reloadCertificate()
+				continue
+			}
+			s.logger.Infof("Got %s, shutting down", sig)
+			return s.shutdown(drain)
+		}
+	}
+}
+
+func (s *Server) reloadCertificate() {
+	if s.certs == nil {
+		return
+	}
+	if err := s.certs.Reload(); err != nil {
+		s.logger.Errorf("Failed to reload TLS certificate - %s", err)
+		return
+	}
+	s.logger.Infof("TLS certificate reloaded")
+}
+
+func (s *Server) shutdown(drain func()) error {
+	if drain != nil {
+		drain()
+	}
+	time.Sleep(s.cfg.DrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), orDefault(s.cfg.ShutdownTimeout, DefaultShutdownTimeout))
+	defer cancel()
+
+	err := s.http.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		s.logger.Warnf("Shutdown deadline exceeded, closing remaining connections")
+		return s.http.Close()
+	}
+	return err
+}
+
+func orDefault(d, fallback time.Duration) time.Duration {
+	if d == 0 {
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/axidex/elliptic/config"
+	"github.com/axidex/elliptic/internal/cypher"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoCertificate = errors.New("server: tls certificate and key paths are required")
+	ErrTLSVersion    = errors.New("server: unsupported tls version")
+	ErrClientCA      = errors.New("server: no certificates found in client CA file")
+)
+
+// selfSignedValidity is how long an auto-generated certificate stays valid.
+const selfSignedValidity = 365 * 24 * time.Hour
+
+// CertReloader serves the certificate loaded from disk and swaps it when
+// Reload is called, so rotated certificates are picked up without a restart.
+type CertReloader struct {
+	certFile, keyFile string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewCertReloader loads the certificate and key pair from the given files.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the certificate and key again. The old pair is kept if the
+// new one can't be loaded.
+func (r *CertReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// NewTLSConfig builds the server TLS configuration. With SelfSigned set, a
+// certificate is generated first if the configured files don't exist yet.
+// With a client CA, clients must present a certificate signed by it.
+func NewTLSConfig(cfg config.TLS) (*tls.Config, *CertReloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, nil, ErrNoCertificate
+	}
+	minVersion, err := tlsVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.SelfSigned && !exists(cfg.CertFile) && !exists(cfg.KeyFile) {
+		if err := GenerateSelfSigned(cfg.CertFile, cfg.KeyFile, cfg.Curve, cfg.Hosts); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	reloader, err := NewCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     minVersion,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if cfg.ClientCA != "" {
+		caPEM, err := os.ReadFile(cfg.ClientCA)
+		if err != nil {
+			return nil, nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, nil, ErrClientCA
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, reloader, nil
+}
+
+func tlsVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrTLSVersion, version)
+	}
+}
+
+// GenerateSelfSigned creates an EC key on the named curve with
+// cypher.GenerateKey and writes it with a self-signed certificate for hosts.
+// Hosts may be DNS names or IP addresses; localhost is used if none are given.
+func GenerateSelfSigned(certFile, keyFile, curveName string, hosts []string) error {
+	if curveName == "" {
+		curveName = cypher.DefaultCurve.Params().Name
+	}
+	curve, ok := cypher.CurveByName(curveName)
+	if !ok {
+		return cypher.ErrInvalidCurve
+	}
+	prv, err := cypher.GenerateKey(rand.Reader, curve.Curve, nil)
+	if err != nil {
+		return err
+	}
+	key := prv.ExportECDSA()
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	if len(hosts) == 0 {
+		hosts = []string{"localhost"}
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: hosts[0]},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := writeFile(keyFile, keyPEM, 0600); err != nil {
+		return err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return writeFile(certFile, certPEM, 0644)
+}
+
+func writeFile(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
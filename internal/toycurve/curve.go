@@ -0,0 +1,269 @@
+// Package toycurve implements point arithmetic on small elliptic curves over
+// prime fields. It is meant for teaching: every value fits in an int64 and
+// the whole group can be enumerated.
+package toycurve
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxModulus bounds the field size, so that products of field elements fit in
+// an int64 and enumerating the group stays cheap.
+const MaxModulus = 65521
+
+var (
+	ErrModulusTooLarge = fmt.Errorf("toycurve: modulus must not exceed %d", MaxModulus)
+	ErrNotPrime        = errors.New("toycurve: modulus must be a prime greater than 3")
+	ErrSingular        = errors.New("toycurve: curve is singular, 4a^3 + 27b^2 = 0 mod p")
+	ErrNotOnCurve      = errors.New("toycurve: point is not on the curve")
+)
+
+// Curve is the short Weierstrass curve y^2 = x^3 + Ax + B over F_P.
+type Curve struct {
+	A, B, P int64
+}
+
+// Point is an affine point of a curve. The point at infinity, the identity of
+// the group, has Infinity set and zero coordinates.
+type Point struct {
+	X, Y     int64
+	Infinity bool
+}
+
+// Infinity is the point at infinity.
+var Infinity = Point{Infinity: true}
+
+// New validates the curve parameters and reduces A and B modulo P.
+func New(a, b, p int64) (*Curve, error) {
+	if p > MaxModulus {
+		return nil, ErrModulusTooLarge
+	}
+	if p <= 3 || !isPrime(p) {
+		return nil, ErrNotPrime
+	}
+
+	c := &Curve{A: mod(a, p), B: mod(b, p), P: p}
+	disc := mod(4*c.pow(c.A, 3)+27*c.mul(c.B, c.B), p)
+	if disc == 0 {
+		return nil, ErrSingular
+	}
+	return c, nil
+}
+
+func (c *Curve) String() string {
+	return fmt.Sprintf("y^2 = x^3 + %dx + %d mod %d", c.A, c.B, c.P)
+}
+
+// IsOnCurve reports whether the point satisfies the curve equation.
+func (c *Curve) IsOnCurve(p Point) bool {
+	if p.Infinity {
+		return true
+	}
+	if p.X < 0 || p.X >= c.P || p.Y < 0 || p.Y >= c.P {
+		return false
+	}
+	return c.mul(p.Y, p.Y) == c.rhs(p.X)
+}
+
+// Points lists every point of the group, starting with the point at infinity.
+func (c *Curve) Points() []Point {
+	roots := c.squareRoots()
+	points := []Point{Infinity}
+	for x := int64(0); x < c.P; x++ {
+		for _, y := range roots[c.rhs(x)] {
+			points = append(points, Point{X: x, Y: y})
+		}
+	}
+	return points
+}
+
+// GroupOrder counts the points of the curve, including the point at infinity.
+func (c *Curve) GroupOrder() int64 {
+	roots := c.squareRoots()
+	n := int64(1)
+	for x := int64(0); x < c.P; x++ {
+		n += int64(len(roots[c.rhs(x)]))
+	}
+	return n
+}
+
+// Neg returns -p.
+func (c *Curve) Neg(p Point) Point {
+	if p.Infinity {
+		return p
+	}
+	return Point{X: p.X, Y: mod(-p.Y, c.P)}
+}
+
+// Add returns p + q using the chord-and-tangent rule.
+func (c *Curve) Add(p, q Point) Point {
+	switch {
+	case p.Infinity:
+		return q
+	case q.Infinity:
+		return p
+	case p.X == q.X && mod(p.Y+q.Y, c.P) == 0:
+		return Infinity
+	case p == q:
+		return c.Double(p)
+	}
+
+	lambda := c.mul(mod(q.Y-p.Y, c.P), c.inv(mod(q.X-p.X, c.P)))
+	return c.line(lambda, p, q)
+}
+
+// Double returns 2p using the tangent at p.
+func (c *Curve) Double(p Point) Point {
+	if p.Infinity || p.Y == 0 {
+		return Infinity
+	}
+
+	lambda := c.mul(mod(3*c.mul(p.X, p.X)+c.A, c.P), c.inv(mod(2*p.Y, c.P)))
+	return c.line(lambda, p, p)
+}
+
+// line returns the third intersection of the line with slope lambda through p
+// and q, reflected over the x-axis.
+func (c *Curve) line(lambda int64, p, q Point) Point {
+	x := mod(c.mul(lambda, lambda)-p.X-q.X, c.P)
+	y := mod(c.mul(lambda, p.X-x)-p.Y, c.P)
+	return Point{X: x, Y: y}
+}
+
+// Step is one operation of the double-and-add ladder. Scalar is the multiple
+// of the base point held after the step.
+type Step struct {
+	Bit       int
+	Operation string
+	Scalar    int64
+	Point     Point
+}
+
+// ScalarMult computes k·p with the left-to-right double-and-add ladder and
+// returns every intermediate value. A negative k multiplies -p by -k reduced
+// modulo the order of p.
+func (c *Curve) ScalarMult(p Point, k int64) (Point, []Step) {
+	if k < 0 {
+		// Reduce before negating: -k overflows for math.MinInt64.
+		p, k = c.Neg(p), -(k % c.Order(p))
+	}
+
+	var steps []Step
+	acc, scalar := Infinity, int64(0)
+	for bit := bitLen(k) - 1; bit >= 0; bit-- {
+		if scalar != 0 {
+			acc, scalar = c.Double(acc), scalar*2
+			steps = append(steps, Step{Bit: bit, Operation: "double", Scalar: scalar, Point: acc})
+		}
+		if k>>bit&1 == 1 {
+			acc, scalar = c.Add(acc, p), scalar+1
+			steps = append(steps, Step{Bit: bit, Operation: "add", Scalar: scalar, Point: acc})
+		}
+	}
+	return acc, steps
+}
+
+// Order returns the order of p, the smallest n > 0 with n·p = O. It divides
+// the group order, so it is found by stripping prime factors from it.
+func (c *Curve) Order(p Point) int64 {
+	n := c.GroupOrder()
+	for _, f := range factor(n) {
+		for n%f.prime == 0 {
+			if q, _ := c.ScalarMult(p, n/f.prime); !q.Infinity {
+				break
+			}
+			n /= f.prime
+		}
+	}
+	return n
+}
+
+func (c *Curve) rhs(x int64) int64 {
+	return mod(c.mul(c.mul(x, x), x)+c.mul(c.A, x)+c.B, c.P)
+}
+
+// squareRoots maps every quadratic residue of F_P to its square roots.
+func (c *Curve) squareRoots() map[int64][]int64 {
+	roots := make(map[int64][]int64, c.P/2+1)
+	for y := int64(0); y < c.P; y++ {
+		sq := c.mul(y, y)
+		roots[sq] = append(roots[sq], y)
+	}
+	return roots
+}
+
+func (c *Curve) mul(a, b int64) int64 {
+	return mod(a*b, c.P)
+}
+
+func (c *Curve) pow(a, e int64) int64 {
+	result := int64(1)
+	a = mod(a, c.P)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = c.mul(result, a)
+		}
+		a = c.mul(a, a)
+	}
+	return result
+}
+
+// inv returns the inverse of a non-zero field element by Fermat's little
+// theorem.
+func (c *Curve) inv(a int64) int64 {
+	return c.pow(a, c.P-2)
+}
+
+func mod(a, p int64) int64 {
+	a %= p
+	if a < 0 {
+		a += p
+	}
+	return a
+}
+
+func bitLen(k int64) int {
+	n := 0
+	for ; k > 0; k >>= 1 {
+		n++
+	}
+	return n
+}
+
+func isPrime(n int64) bool {
+	if n < 2 {
+		return false
+	}
+	for d := int64(2); d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type primePower struct {
+	prime    int64
+	exponent int
+}
+
+// factor returns the prime factorisation of n by trial division.
+func factor(n int64) []primePower {
+	var factors []primePower
+	for d := int64(2); d*d <= n; d++ {
+		if n%d != 0 {
+			continue
+		}
+		f := primePower{prime: d}
+		for n%d == 0 {
+			n /= d
+			f.exponent++
+		}
+		factors = append(factors, f)
+	}
+	if n > 1 {
+		factors = append(factors, primePower{prime: n, exponent: 1})
+	}
+	return factors
+}
@@ -0,0 +1,30 @@
+package toycurve
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScalarMultNegative(t *testing.T) {
+	c, err := New(2, 3, 97)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Point{X: 3, Y: 6}
+	if !c.IsOnCurve(p) {
+		t.Fatal("test point is not on the curve")
+	}
+	n := c.Order(p)
+
+	for _, k := range []int64{-1, -n, -n - 1, -3*n + 2, math.MinInt64, math.MinInt64 + 1} {
+		got, _ := c.ScalarMult(p, k)
+		// k·p = (k mod n)·p, with the remainder taken non-negative.
+		want, _ := c.ScalarMult(p, (k%n+n)%n)
+		if got != want {
+			t.Errorf("%d·p = %v, want %v", k, got, want)
+		}
+		if !c.IsOnCurve(got) {
+			t.Errorf("%d·p = %v is not on the curve", k, got)
+		}
+	}
+}
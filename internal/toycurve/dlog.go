@@ -0,0 +1,238 @@
+package toycurve
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// MaxSolverOrder is the largest generator order the discrete log solvers
+// accept.
+const MaxSolverOrder = 1 << 16
+
+var (
+	ErrOrderTooLarge = fmt.Errorf("toycurve: generator order must not exceed %d", MaxSolverOrder)
+	ErrNoSolution    = errors.New("toycurve: point is not a multiple of the generator")
+	ErrUnknownMethod = errors.New("toycurve: unknown discrete log method")
+)
+
+// Discrete log methods.
+const (
+	BabyStepGiantStep = "bsgs"
+	PollardRho        = "rho"
+	PohligHellman     = "pohlig-hellman"
+)
+
+// Methods lists the available discrete log methods.
+var Methods = []string{BabyStepGiantStep, PollardRho, PohligHellman}
+
+// Solution is the private scalar k with q = k·g found by a solver, along with
+// the work it took.
+type Solution struct {
+	Method     string
+	K          int64
+	Order      int64
+	Iterations int64
+	Duration   time.Duration
+}
+
+// SolveDiscreteLog recovers k in [0, n) with q = k·g, where n is the order of
+// g, using the given method.
+func (c *Curve) SolveDiscreteLog(method string, g, q Point) (Solution, error) {
+	var solve func(g, q Point, n int64) (int64, int64, error)
+	switch method {
+	case BabyStepGiantStep:
+		solve = c.bsgs
+	case PollardRho:
+		solve = c.rho
+	case PohligHellman:
+		solve = c.pohligHellman
+	default:
+		return Solution{}, ErrUnknownMethod
+	}
+
+	if !c.IsOnCurve(g) || !c.IsOnCurve(q) {
+		return Solution{}, ErrNotOnCurve
+	}
+	n := c.Order(g)
+	if n > MaxSolverOrder {
+		return Solution{}, ErrOrderTooLarge
+	}
+
+	start := time.Now()
+	k, iterations, err := solve(g, q, n)
+	if err != nil {
+		return Solution{}, err
+	}
+	return Solution{
+		Method:     method,
+		K:          k,
+		Order:      n,
+		Iterations: iterations,
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// bsgs is Shanks' baby-step giant-step: with m = ceil(sqrt(n)) it stores j·g
+// for j < m and walks q - i·m·g until it hits the table.
+func (c *Curve) bsgs(g, q Point, n int64) (k, iterations int64, err error) {
+	m := int64(1)
+	for m*m < n {
+		m++
+	}
+
+	baby := make(map[Point]int64, m)
+	p := Infinity
+	for j := int64(0); j < m; j++ {
+		if _, ok := baby[p]; !ok {
+			baby[p] = j
+		}
+		p = c.Add(p, g)
+		iterations++
+	}
+
+	stride, _ := c.ScalarMult(c.Neg(g), m)
+	p = q
+	for i := int64(0); i < m; i++ {
+		iterations++
+		if j, ok := baby[p]; ok {
+			return mod(i*m+j, n), iterations, nil
+		}
+		p = c.Add(p, stride)
+	}
+	return 0, iterations, ErrNoSolution
+}
+
+// rhoState is a point x = a·g + b·q of Pollard's random walk.
+type rhoState struct {
+	x    Point
+	a, b int64
+}
+
+// rhoStep moves the walk by adding g, doubling or adding q depending on the
+// partition x mod 3.
+func (c *Curve) rhoStep(s rhoState, g, q Point, n int64) rhoState {
+	switch s.x.X % 3 {
+	case 0:
+		return rhoState{x: c.Add(s.x, g), a: mod(s.a+1, n), b: s.b}
+	case 1:
+		return rhoState{x: c.Double(s.x), a: mod(2*s.a, n), b: mod(2*s.b, n)}
+	default:
+		return rhoState{x: c.Add(s.x, q), a: s.a, b: mod(s.b+1, n)}
+	}
+}
+
+// rho is Pollard's rho with Floyd cycle detection. A collision
+// a1·g + b1·q = a2·g + b2·q gives (b2 - b1)·k = a1 - a2 mod n; when the
+// coefficient is not invertible every candidate is checked.
+func (c *Curve) rho(g, q Point, n int64) (k, iterations int64, err error) {
+	if n == 1 {
+		if q.Infinity {
+			return 0, 0, nil
+		}
+		return 0, 0, ErrNoSolution
+	}
+
+	for attempt := 0; attempt < 32; attempt++ {
+		a, b := rand.Int64N(n), rand.Int64N(n)
+		ag, _ := c.ScalarMult(g, a)
+		bq, _ := c.ScalarMult(q, b)
+		tortoise := rhoState{x: c.Add(ag, bq), a: a, b: b}
+		hare := tortoise
+
+		for i := int64(0); i < 4*n+16; i++ {
+			iterations++
+			tortoise = c.rhoStep(tortoise, g, q, n)
+			hare = c.rhoStep(c.rhoStep(hare, g, q, n), g, q, n)
+			if tortoise.x != hare.x {
+				continue
+			}
+
+			for _, candidate := range solveLinear(mod(hare.b-tortoise.b, n), mod(tortoise.a-hare.a, n), n) {
+				if p, _ := c.ScalarMult(g, candidate); p == q {
+					return candidate, iterations, nil
+				}
+			}
+			break
+		}
+	}
+	return 0, iterations, ErrNoSolution
+}
+
+// pohligHellman reduces the problem to the prime power subgroups of <g>,
+// solves each digit with baby-step giant-step in a subgroup of prime order and
+// recombines the results with the Chinese remainder theorem.
+func (c *Curve) pohligHellman(g, q Point, n int64) (k, iterations int64, err error) {
+	var residues, moduli []int64
+	for _, f := range factor(n) {
+		pe := int64(1)
+		for i := 0; i < f.exponent; i++ {
+			pe *= f.prime
+		}
+
+		// g0 generates the subgroup of order p.
+		g0, _ := c.ScalarMult(g, n/f.prime)
+		x, power := int64(0), int64(1)
+		for i := 0; i < f.exponent; i++ {
+			// Strip the digits found so far and project into the subgroup.
+			xg, _ := c.ScalarMult(g, x)
+			h, _ := c.ScalarMult(c.Add(q, c.Neg(xg)), n/(power*f.prime))
+			digit, steps, err := c.bsgs(g0, h, f.prime)
+			iterations += steps
+			if err != nil {
+				return 0, iterations, err
+			}
+			x += digit * power
+			power *= f.prime
+		}
+		residues = append(residues, x%pe)
+		moduli = append(moduli, pe)
+	}
+
+	k = crt(residues, moduli)
+	if p, _ := c.ScalarMult(g, k); p != q {
+		return 0, iterations, ErrNoSolution
+	}
+	return k, iterations, nil
+}
+
+// solveLinear returns every x in [0, n) with a·x = b mod n.
+func solveLinear(a, b, n int64) []int64 {
+	if a == 0 {
+		// A degenerate collision says nothing about x.
+		return nil
+	}
+	d, inv, _ := egcd(a, n)
+	if b%d != 0 {
+		return nil
+	}
+	step := n / d
+	x0 := mod(mod(inv, step)*(b/d), step)
+	solutions := make([]int64, 0, d)
+	for t := int64(0); t < d; t++ {
+		solutions = append(solutions, x0+t*step)
+	}
+	return solutions
+}
+
+// crt combines x = r_i mod m_i for pairwise coprime moduli.
+func crt(residues, moduli []int64) int64 {
+	x, m := int64(0), int64(1)
+	for i := range residues {
+		_, inv, _ := egcd(mod(m, moduli[i]), moduli[i])
+		t := mod((residues[i]-x)%moduli[i]*mod(inv, moduli[i]), moduli[i])
+		x += m * t
+		m *= moduli[i]
+	}
+	return mod(x, m)
+}
+
+// egcd returns d = gcd(a, b) and x, y with a·x + b·y = d.
+func egcd(a, b int64) (d, x, y int64) {
+	if b == 0 {
+		return a, 1, 0
+	}
+	d, x1, y1 := egcd(b, a%b)
+	return d, y1, x1 - (a/b)*y1
+}
@@ -24,8 +24,8 @@ class CypherClient:
         return Keys(resp_json['private'], resp_json['public'])
 
     def encrypt(self, text: str, public_key: str) -> bytes:
-        resp = requests.post(url=self.base_url + '/api/cypher/elliptic/encrypt', files={
-            'text': ('', text),
+        resp = requests.post(url=self.base_url + '/api/cypher/elliptic/encrypt/file', files={
+            'file': ('data.txt', text),
             'pemKey': ('public_key.pem', public_key),
         })
         resp.raise_for_status()
@@ -35,15 +35,13 @@ class CypherClient:
     def decrypt(self, encrypted_text: bytes, private_key: str) -> str:
         files = {
             'pemKey': ('private_key.pem', private_key),
-            'encryptedData': ('encrypted_data.bin', encrypted_text)
+            'file': ('data.txt.enc', encrypted_text)
         }
 
-        resp = requests.post(url=self.base_url + '/api/cypher/elliptic/decrypt',  files=files)
+        resp = requests.post(url=self.base_url + '/api/cypher/elliptic/decrypt/file',  files=files)
         resp.raise_for_status()
 
-        resp_json = resp.json()
-
-        return resp_json['text']
+        return resp.content.decode()
 
 
 def main():